@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+)
+
+// AuditEntry records a value transformed by a conversion function
+type AuditEntry struct {
+	Path      string
+	FromValue interface{}
+	ToValue   interface{}
+	// Name of the conversion function, like main.maskIBAN.
+	// Empty for functions made by conversion factories.
+	Converter string
+}
+
+// AuditLog collects the values transformed by conversion functions in Map calls
+// it is passed to with WithAudit, like for compliance logging of regulated fields.
+// It is safe for concurrent use.
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// Entries returns the recorded entries in order of conversion
+func (al *AuditLog) Entries() []AuditEntry {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	return append([]AuditEntry(nil), al.entries...)
+}
+
+// Reset removes all recorded entries
+func (al *AuditLog) Reset() {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.entries = nil
+}
+
+func (al *AuditLog) record(entry AuditEntry) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.entries = append(al.entries, entry)
+}
+
+// WithAudit records all conversions into log. Usually passed to a single Map call,
+// as recording every converted value adds overhead.
+func WithAudit(log *AuditLog) Option {
+	return func(o *options) {
+		o.audit = log
+	}
+}
+
+// Record a conversion of srcRv into dstRv if auditing is enabled
+func (m *mapping) auditConversion(dstRv, srcRv reflect.Value) {
+	if m.opts.audit == nil {
+		return
+	}
+	m.opts.audit.record(AuditEntry{
+		Path:      m.pathString(),
+		FromValue: auditValue(srcRv),
+		ToValue:   auditValue(dstRv),
+		Converter: m.convFuncName(dstRv.Type(), srcRv.Type()),
+	})
+}
+
+func auditValue(rv reflect.Value) interface{} {
+	if !rv.CanInterface() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// Find the name of the conversion function for (dst-src) pair, in the order of findConvFunc
+func (m *mapping) convFuncName(dstType, srcType reflect.Type) string {
+	for i := len(m.opts.convFuncs) - 1; i >= 0; i-- {
+		if cf := m.opts.convFuncs[i]; cf.fromType == srcType && cf.toType == dstType {
+			return cf.name
+		}
+	}
+	var owner, scoped, plain, underlying string
+	for _, info := range m.converters {
+		switch {
+		case info.Underlying:
+			if info.From.Kind() == srcType.Kind() && info.To == dstType {
+				underlying = info.Name
+			}
+		case info.From != srcType || info.To != dstType:
+		case info.Owner != nil:
+			if info.Owner == m.owner {
+				owner = info.Name
+			}
+		case info.Scope != "":
+			if info.Scope == m.opts.scope {
+				scoped = info.Name
+			}
+		default:
+			plain = info.Name
+		}
+	}
+	for _, name := range []string{owner, scoped, plain} {
+		if name != "" {
+			return name
+		}
+	}
+	if _, ok := m.findFactoryFunc(dstType, srcType); ok {
+		return ""
+	}
+	return underlying
+}
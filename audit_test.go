@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func maskIBAN(iban string) MaskedIBAN {
+	return MaskedIBAN("****" + iban[len(iban)-4:])
+}
+
+type MaskedIBAN string
+
+// Conversions are recorded with their path, values and function
+func TestAuditLog(t *testing.T) {
+	type Account struct {
+		Owner string
+		IBAN  string
+	}
+	type AccountDto struct {
+		Owner string
+		IBAN  MaskedIBAN
+	}
+	type Statement struct {
+		Accounts []Account
+	}
+	type StatementDto struct {
+		Accounts []AccountDto
+	}
+
+	mapper := Mapper{}
+	mapper.AddConvFunc(maskIBAN)
+
+	log := AuditLog{}
+	statement := StatementDto{}
+	err := mapper.Map(&statement, Statement{Accounts: []Account{{Owner: "Bob", IBAN: "DE0012345678"}}}, WithAudit(&log))
+	assert.Nil(t, err)
+	assert.Equal(t, []AuditEntry{{
+		Path:      "Accounts[0].IBAN",
+		FromValue: "DE0012345678",
+		ToValue:   MaskedIBAN("****5678"),
+		Converter: "github.com/dranikpg/dto-mapper.maskIBAN",
+	}}, log.Entries())
+
+	// call functions take precedence
+	log.Reset()
+	err = mapper.Map(&statement, Statement{Accounts: []Account{{IBAN: "DE0012345678"}}}, WithAudit(&log),
+		WithConv(func(iban string) MaskedIBAN { return MaskedIBAN(strings.Repeat("*", len(iban))) }))
+	assert.Nil(t, err)
+	assert.Len(t, log.Entries(), 1)
+	assert.Equal(t, MaskedIBAN("************"), log.Entries()[0].ToValue)
+	assert.True(t, strings.HasPrefix(log.Entries()[0].Converter, "github.com/dranikpg/dto-mapper.TestAuditLog."))
+
+	// nothing is recorded without the option
+	log.Reset()
+	err = mapper.Map(&statement, Statement{Accounts: []Account{{IBAN: "DE0012345678"}}})
+	assert.Nil(t, err)
+	assert.Empty(t, log.Entries())
+}
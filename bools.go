@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+)
+
+// BoolTokens defines strings accepted as boolean values
+// The first token of each list is used when mapping bools to strings
+type BoolTokens struct {
+	True  []string
+	False []string
+}
+
+// DefaultBoolTokens are used by AddBoolConvFuncs if no tokens are given
+var DefaultBoolTokens = BoolTokens{
+	True:  []string{"true", "1", "yes", "y", "on"},
+	False: []string{"false", "0", "no", "n", "off", ""},
+}
+
+var boolRfType = reflect.TypeOf(false)
+
+var integerRfTypes = []reflect.Type{
+	reflect.TypeOf(int(0)), reflect.TypeOf(int8(0)), reflect.TypeOf(int16(0)),
+	reflect.TypeOf(int32(0)), reflect.TypeOf(int64(0)),
+	reflect.TypeOf(uint(0)), reflect.TypeOf(uint8(0)), reflect.TypeOf(uint16(0)),
+	reflect.TypeOf(uint32(0)), reflect.TypeOf(uint64(0)),
+}
+
+// Parse a string by case insensitive tokens
+func (bt BoolTokens) parse(s string) (bool, error) {
+	s = strings.TrimSpace(s)
+	for _, token := range bt.True {
+		if strings.EqualFold(token, s) {
+			return true, nil
+		}
+	}
+	for _, token := range bt.False {
+		if strings.EqualFold(token, s) {
+			return false, nil
+		}
+	}
+	return false, ParseError{Value: s, Type: boolRfType}
+}
+
+// Format a bool by the first token
+func (bt BoolTokens) format(b bool) string {
+	tokens := bt.False
+	if b {
+		tokens = bt.True
+	}
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[0]
+}
+
+// Make a conversion function of the given signature from a closure
+func makeConvFunc(in, out reflect.Type, fn func(reflect.Value) reflect.Value) interface{} {
+	ft := reflect.FuncOf([]reflect.Type{in}, []reflect.Type{out}, false)
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{fn(args[0])}
+	}).Interface()
+}
+
+// AddBoolConvFuncs adds conversion functions that map strings and integers to bools
+// and vice versa. Strings are matched case insensitive against the tokens,
+// integers are true if they are non zero. Pointers to those are dereferenced as usual.
+//
+// Uses DefaultBoolTokens if tokens are empty
+func (m *Mapper) AddBoolConvFuncs(tokens BoolTokens) {
+	if len(tokens.True)+len(tokens.False) == 0 {
+		tokens = DefaultBoolTokens
+	}
+
+	// strings
+	m.AddConvFunc(func(s string) (bool, error) {
+		return tokens.parse(s)
+	})
+	m.AddConvFunc(func(b bool) string {
+		return tokens.format(b)
+	})
+
+	// integers
+	for _, intType := range integerRfTypes {
+		intType := intType
+		m.AddConvFunc(makeConvFunc(intType, boolRfType, func(v reflect.Value) reflect.Value {
+			return reflect.ValueOf(!v.IsZero())
+		}))
+		m.AddConvFunc(makeConvFunc(boolRfType, intType, func(v reflect.Value) reflect.Value {
+			out := reflect.New(intType).Elem()
+			if v.Bool() {
+				out.Set(reflect.ValueOf(1).Convert(intType))
+			}
+			return out
+		}))
+	}
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Map strings, integers and pointers to bools and back
+func TestBoolConvFuncs(t *testing.T) {
+	yes := "YES"
+	from := struct {
+		Active   string
+		Verified *string
+		Admin    int
+		Count    uint8
+	}{"on", &yes, 0, 3}
+	var out struct {
+		Active   bool
+		Verified bool
+		Admin    bool
+		Count    bool
+	}
+
+	m := Mapper{}
+	m.AddBoolConvFuncs(BoolTokens{})
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.True(t, out.Active)
+	assert.True(t, out.Verified)
+	assert.False(t, out.Admin)
+	assert.True(t, out.Count)
+
+	var back struct {
+		Active string
+		Admin  int
+		Count  uint8
+	}
+	err = m.Map(&back, out)
+	assert.Nil(t, err)
+	assert.Equal(t, "true", back.Active)
+	assert.Equal(t, 0, back.Admin)
+	assert.Equal(t, uint8(1), back.Count)
+}
+
+// Use custom tokens and fail on unknown ones
+func TestBoolConvFuncsTokens(t *testing.T) {
+	m := Mapper{}
+	m.AddBoolConvFuncs(BoolTokens{True: []string{"ja"}, False: []string{"nein"}})
+
+	var out struct{ Active bool }
+	err := m.Map(&out, struct{ Active string }{"Ja"})
+	assert.Nil(t, err)
+	assert.True(t, out.Active)
+
+	err = m.Map(&out, struct{ Active string }{"yes"})
+	assert.ErrorIs(t, err, ParseError{Value: "yes", Type: reflect.TypeOf(false)})
+
+	var back struct{ Active string }
+	err = m.Map(&back, struct{ Active bool }{false})
+	assert.Nil(t, err)
+	assert.Equal(t, "nein", back.Active)
+}
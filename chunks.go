@@ -0,0 +1,88 @@
+package dto
+
+import "reflect"
+
+// MapChunks maps the slice src in chunks of chunkSize elements into the slice dst
+// and passes every chunk to fn, which has to be a func([]D) error for a dst of type *[]D.
+// The backing array of dst is reused for all chunks, so memory stays bounded
+// when streaming large exports to files or message queues. fn must not retain chunks.
+// After returning, dst holds the last chunk.
+//
+// Mapping stops as soon as fn returns an error. fn is not called for empty sources.
+// Panics if chunkSize is not positive or fn is not a valid chunk function
+func (m *Mapper) MapChunks(dst, src interface{}, chunkSize int, fn interface{}, opts ...Option) error {
+	if chunkSize <= 0 {
+		panic("Bad chunk size")
+	}
+	dstRv, srcRv := reflectValueRemovePtr(dst), reflectValueRemovePtr(src)
+	fnRv := reflect.ValueOf(fn)
+	if dstRv.Kind() == reflect.Slice && !isChunkFunc(fnRv.Type(), dstRv.Type()) {
+		panic("Bad chunk function")
+	}
+
+	mp := m.newMapping(opts...)
+	return mp.track(dstRv, srcRv, func() error {
+		if dstRv.Kind() != reflect.Slice || (srcRv.Kind() != reflect.Slice && srcRv.Kind() != reflect.Array) {
+			return NoValidMappingError{ToType: dstRv.Type(), FromType: srcRv.Type()}
+		}
+		return mp.mapChunks(dstRv, srcRv, chunkSize, fnRv)
+	})
+}
+
+// MapChunks maps the slice src in chunks into the slice dst and passes every chunk to fn
+func MapChunks(dst, src interface{}, chunkSize int, fn interface{}, opts ...Option) error {
+	m := Mapper{}
+	return m.MapChunks(dst, src, chunkSize, fn, opts...)
+}
+
+// Check if a function takes chunks of the given slice type and returns an error
+func isChunkFunc(fnType, sliceType reflect.Type) bool {
+	return fnType.Kind() == reflect.Func && fnType.NumIn() == 1 && fnType.In(0) == sliceType &&
+		fnType.NumOut() == 1 && fnType.Out(0) == errorRfType
+}
+
+// Map a slice in chunks, reusing a single buffer
+func (m *mapping) mapChunks(dstRv, srcRv reflect.Value, chunkSize int, fn reflect.Value) error {
+	// don't allocate more than the source holds
+	if srcRv.Len() < chunkSize {
+		chunkSize = srcRv.Len()
+	}
+	buffer := reflect.MakeSlice(dstRv.Type(), chunkSize, chunkSize)
+	zero := reflect.Zero(dstRv.Type().Elem())
+
+	flush := func(n int) error {
+		chunk := buffer.Slice(0, n)
+		dstRv.Set(chunk)
+		return errorFromReflectValue(fn.Call([]reflect.Value{chunk})[0])
+	}
+
+	n, total := 0, 0
+	for i := 0; i < srcRv.Len(); i++ {
+		if m.opts.nilPolicy == DropNil && isNilPointer(srcRv.Index(i)) {
+			continue
+		}
+		// don't leak values of previous chunks
+		buffer.Index(n).Set(zero)
+		m.pushIndex(total)
+		err := m.mapValue(buffer.Index(n), srcRv.Index(i))
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		n++
+		total++
+		if n == chunkSize {
+			if err := flush(n); err != nil {
+				return err
+			}
+			n = 0
+		}
+	}
+	if total == 0 {
+		dstRv.Set(buffer.Slice(0, 0))
+	}
+	if n > 0 {
+		return flush(n)
+	}
+	return nil
+}
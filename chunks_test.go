@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Slices are mapped in chunks with a shared buffer
+func TestMapChunks(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+
+	var names [][]string
+	var out []ProductDto
+	err := MapChunks(&out, commonProducts, 3, func(chunk []ProductDto) error {
+		var chunkNames []string
+		for _, p := range chunk {
+			chunkNames = append(chunkNames, p.Name)
+		}
+		names = append(names, chunkNames)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	var expected [][]string
+	for i := 0; i < len(commonProducts); i += 3 {
+		var chunkNames []string
+		for j := i; j < i+3 && j < len(commonProducts); j++ {
+			chunkNames = append(chunkNames, commonProducts[j].Name)
+		}
+		expected = append(expected, chunkNames)
+	}
+	assert.Equal(t, expected, names)
+	assert.Equal(t, cap(out), 3)
+
+	// buffers are not larger than sources
+	err = MapChunks(&out, commonProducts[:2], 1000, func(chunk []ProductDto) error { return nil })
+	assert.Nil(t, err)
+	assert.Len(t, out, 2)
+	assert.Equal(t, cap(out), 2)
+
+	// empty sources don't produce chunks
+	err = MapChunks(&out, []Product{}, 3, func(chunk []ProductDto) error {
+		return errors.New("unexpected chunk")
+	})
+	assert.Nil(t, err)
+	assert.Len(t, out, 0)
+}
+
+// Errors of chunk functions stop mapping
+func TestMapChunksError(t *testing.T) {
+	failed := errors.New("queue unavailable")
+	calls := 0
+
+	var out []int64
+	err := MapChunks(&out, make([]int, 10), 2, func(chunk []int64) error {
+		calls++
+		return failed
+	})
+	assert.Equal(t, failed, err)
+	assert.Equal(t, 1, calls)
+
+	assert.Panics(t, func() {
+		MapChunks(&out, make([]int, 10), 2, func(chunk []int) error { return nil })
+	})
+	assert.Panics(t, func() {
+		MapChunks(&out, make([]int, 10), 0, func(chunk []int64) error { return nil })
+	})
+}
@@ -0,0 +1,204 @@
+// Command dtogen generates the declaration of a DTO struct from a source struct,
+// so field names match and the DTO is mapped without tags.
+//
+//	dtogen -type User -ignore PasswordHash -redact Email -json -pkg api -o user_dto.go
+//
+// Types declared in the source package are not qualified,
+// so the DTO should be generated into the same package or adjusted by hand.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	dto "github.com/dranikpg/dto-mapper"
+)
+
+func main() {
+	var (
+		typeName = flag.String("type", "", "name of the source struct type")
+		dir      = flag.String("dir", ".", "directory of the source package")
+		name     = flag.String("name", "", "name of the DTO type, defaults to the type name with a Dto suffix")
+		pkg      = flag.String("pkg", "", "package of the generated file, only the declaration is emitted if empty")
+		ignore   = flag.String("ignore", "", "comma separated fields to leave out")
+		redact   = flag.String("redact", "", "comma separated fields to declare, but never map")
+		json     = flag.Bool("json", false, "add json tags with snake_case names")
+		out      = flag.String("o", "", "output file, defaults to stdout")
+	)
+	flag.Parse()
+	if *typeName == "" {
+		fmt.Fprintln(os.Stderr, "dtogen: -type is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	source, err := generate(*dir, *typeName, dto.GenerateRules{
+		Name:     *name,
+		Package:  *pkg,
+		Ignore:   splitList(*ignore),
+		Redact:   splitList(*redact),
+		JSONTags: *json,
+	})
+	if err == nil {
+		if *out == "" {
+			_, err = os.Stdout.Write(source)
+		} else {
+			err = os.WriteFile(*out, source, 0644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dtogen:", err)
+		os.Exit(1)
+	}
+}
+
+// Split a comma separated list, dropping empty entries
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Parsed source package
+type sourcePackage struct {
+	fset  *token.FileSet
+	types map[string]*ast.StructType
+	files map[string]*ast.File
+}
+
+// Generate the DTO of a struct type declared in the package in dir
+func generate(dir, typeName string, rules dto.GenerateRules) ([]byte, error) {
+	sp, err := parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sp.types[typeName]; !ok {
+		return nil, fmt.Errorf("Struct type %v not found in %v", typeName, dir)
+	}
+	fields, err := sp.collectFields(typeName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dto.GenerateDto(typeName, fields, rules)
+}
+
+// Parse the non-test files of a package and collect its struct types
+func parseDir(dir string) (*sourcePackage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sp := &sourcePackage{
+		fset:  token.NewFileSet(),
+		types: make(map[string]*ast.StructType),
+		files: make(map[string]*ast.File),
+	}
+	for _, p := range paths {
+		if strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(sp.fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if spec, ok := node.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					sp.types[spec.Name.Name] = st
+					sp.files[spec.Name.Name] = file
+				}
+			}
+			return true
+		})
+	}
+	return sp, nil
+}
+
+// Collect the exported fields of a struct type, flattening embedded structs of the package
+func (sp *sourcePackage) collectFields(typeName string, fields []dto.DtoField) ([]dto.DtoField, error) {
+	file := sp.files[typeName]
+	for _, field := range sp.types[typeName].Fields.List {
+		if field.Tag != nil && isIgnored(field.Tag.Value) {
+			continue
+		}
+		switch field.Type.(type) {
+		case *ast.FuncType, *ast.ChanType:
+			continue
+		}
+		if len(field.Names) == 0 {
+			embedded := field.Type
+			if star, ok := embedded.(*ast.StarExpr); ok {
+				embedded = star.X
+			}
+			if ident, ok := embedded.(*ast.Ident); ok && sp.types[ident.Name] != nil {
+				var err error
+				if fields, err = sp.collectFields(ident.Name, fields); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		var expr bytes.Buffer
+		if err := printer.Fprint(&expr, sp.fset, field.Type); err != nil {
+			return nil, err
+		}
+		imports := fileImports(file, field.Type)
+		for _, name := range field.Names {
+			if name.IsExported() {
+				fields = append(fields, dto.DtoField{Name: name.Name, Type: expr.String(), Imports: imports})
+			}
+		}
+	}
+	return fields, nil
+}
+
+// Check if a raw struct tag ignores a field
+func isIgnored(rawTag string) bool {
+	tag, err := strconv.Unquote(rawTag)
+	if err != nil {
+		return false
+	}
+	for _, option := range strings.Split(reflect.StructTag(tag).Get("dto"), ",") {
+		if strings.TrimSpace(option) == "ignore" {
+			return true
+		}
+	}
+	return reflect.StructTag(tag).Get("copier") == "-"
+}
+
+// Find the import paths of packages referenced by a type expression
+func fileImports(file *ast.File, expr ast.Expr) []string {
+	var paths []string
+	ast.Inspect(expr, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			for _, spec := range file.Imports {
+				importPath, _ := strconv.Unquote(spec.Path.Value)
+				if (spec.Name != nil && spec.Name.Name == ident.Name) || (spec.Name == nil && path.Base(importPath) == ident.Name) {
+					paths = append(paths, importPath)
+				}
+			}
+		}
+		return false
+	})
+	return paths
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	dto "github.com/dranikpg/dto-mapper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSource = `package model
+
+import (
+	"time"
+
+	uuid "github.com/google/uuid"
+)
+
+type Base struct {
+	ID uuid.UUID
+}
+
+type User struct {
+	Base
+	Name, Email string
+	CreatedAt   time.Time
+	Password    string ` + "`dto:\"ignore\"`" + `
+	Updates     chan int
+	internal    int
+}
+`
+
+// DTOs are generated from struct types in source files
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "user.go"), []byte(testSource), 0644)
+	assert.Nil(t, err)
+
+	source, err := generate(dir, "User", dto.GenerateRules{Package: "model", Redact: []string{"Email"}})
+	assert.Nil(t, err)
+	assert.Equal(t, `package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserDto is a DTO of User
+type UserDto struct {
+	ID        uuid.UUID
+	Name      string
+	Email     string `+"`"+`dto:"ignore"`+"`"+`
+	CreatedAt time.Time
+}
+`, string(source))
+
+	_, err = generate(dir, "Account", dto.GenerateRules{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,513 @@
+package dto
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeRfType = reflect.TypeOf(time.Time{})
+
+// ==================================== Keyed collections =====================
+
+// Dereference a slice source for mapping into dst
+// Returns false if mapping has to stop with the returned error (if any)
+func (m *mapping) derefSliceSource(dstRv, srcRv reflect.Value) (reflect.Value, bool, error) {
+	for srcRv.Kind() == reflect.Ptr {
+		if srcRv.IsNil() {
+			return srcRv, false, m.mapNil(dstRv, srcRv)
+		}
+		srcRv = srcRv.Elem()
+	}
+	if srcRv.Kind() != reflect.Slice && srcRv.Kind() != reflect.Array {
+		return srcRv, false, NoValidMappingError{
+			ToType:   dstRv.Type(),
+			FromType: srcRv.Type(),
+		}
+	}
+	return srcRv, true, nil
+}
+
+// Iterate over non-nil slice elements with keys mapped from their keyField
+func (m *mapping) forEachKeyed(keyType reflect.Type, srcRv reflect.Value, keyField string,
+	fn func(key, elem reflect.Value) error) error {
+	for i := 0; i < srcRv.Len(); i++ {
+		elem := srcRv.Index(i)
+		if elem.Kind() == reflect.Ptr && elem.IsNil() {
+			continue
+		}
+		field, ok := findStructField(elem, keyField)
+		if !ok {
+			return FieldNotFoundError{
+				Type:  elem.Type(),
+				Field: keyField,
+			}
+		}
+		key := reflect.New(keyType).Elem()
+		if err := m.mapValue(key, field); err != nil {
+			return err
+		}
+		if err := fn(key, elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check if a type can hold the groups of a groupby tag
+func isGroupsType(rfType reflect.Type) bool {
+	return rfType.Kind() == reflect.Map && rfType.Elem().Kind() == reflect.Slice
+}
+
+// Map a slice to a map keyed by a field of its elements
+func (m *mapping) mapSliceToIndex(dstRv, srcRv reflect.Value, keyField string) error {
+	if dstRv.Kind() != reflect.Map {
+		return TagError{Tag: "index", Type: dstRv.Type(), Reason: "not a map"}
+	}
+	srcRv, ok, err := m.derefSliceSource(dstRv, srcRv)
+	if !ok {
+		return err
+	}
+
+	dstRv.Set(reflect.MakeMapWithSize(dstRv.Type(), srcRv.Len()))
+	return m.forEachKeyed(dstRv.Type().Key(), srcRv, keyField, func(key, elem reflect.Value) error {
+		value := reflect.New(dstRv.Type().Elem()).Elem()
+		m.pushKey(key)
+		err := m.mapValue(value, elem)
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		dstRv.SetMapIndex(key, value)
+		return nil
+	})
+}
+
+// Map a slice to a map of slices grouped by a field of its elements
+func (m *mapping) mapSliceToGroups(dstRv, srcRv reflect.Value, keyField string) error {
+	if !isGroupsType(dstRv.Type()) {
+		return TagError{Tag: "groupby", Type: dstRv.Type(), Reason: "not a map of slices"}
+	}
+	srcRv, ok, err := m.derefSliceSource(dstRv, srcRv)
+	if !ok {
+		return err
+	}
+
+	groupType := dstRv.Type().Elem()
+	dstRv.Set(reflect.MakeMap(dstRv.Type()))
+	return m.forEachKeyed(dstRv.Type().Key(), srcRv, keyField, func(key, elem reflect.Value) error {
+		group := dstRv.MapIndex(key)
+		if !group.IsValid() {
+			group = reflect.MakeSlice(groupType, 0, 1)
+		}
+		value := reflect.New(groupType.Elem()).Elem()
+		m.pushKey(key)
+		m.pushIndex(group.Len())
+		err := m.mapValue(value, elem)
+		m.popPath()
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		dstRv.SetMapIndex(key, reflect.Append(group, value))
+		return nil
+	})
+}
+
+// ==================================== Slice unwrapping ======================
+
+// Map a slice element to a single value by the unwrap policy
+func (m *mapping) unwrapSlice(dstRv, srcRv reflect.Value, policy UnwrapPolicy) error {
+	for srcRv.Kind() == reflect.Ptr {
+		if srcRv.IsNil() {
+			return m.mapNil(dstRv, srcRv)
+		}
+		srcRv = srcRv.Elem()
+	}
+	if srcRv.Kind() != reflect.Slice || dstRv.Kind() == reflect.Slice {
+		return m.mapValue(dstRv, srcRv)
+	}
+
+	switch {
+	case srcRv.Len() == 0:
+		return m.mapNil(dstRv, srcRv)
+	case srcRv.Len() > 1 && policy == UnwrapSingle:
+		return AmbiguousSliceError{
+			ToType:   dstRv.Type(),
+			FromType: srcRv.Type(),
+			Len:      srcRv.Len(),
+		}
+	}
+	return m.mapValue(dstRv, srcRv.Index(0))
+}
+
+// Map a single value to a single element slice
+// Slices and maps are mapped as usual, arrays element by element
+func (m *mapping) wrapValue(dstRv, srcRv reflect.Value) error {
+	for srcRv.Kind() == reflect.Ptr {
+		if srcRv.IsNil() {
+			return m.mapNil(dstRv, srcRv)
+		}
+		srcRv = srcRv.Elem()
+	}
+	switch {
+	case srcRv.Kind() == reflect.Array && dstRv.Kind() == reflect.Slice:
+		return m.mapSlice(dstRv, srcRv)
+	case srcRv.Kind() == reflect.Slice, srcRv.Kind() == reflect.Array, srcRv.Kind() == reflect.Map:
+		return m.mapValue(dstRv, srcRv)
+	}
+	if dstRv.Kind() != reflect.Slice {
+		return m.mapValue(dstRv, srcRv)
+	}
+
+	slice := reflect.MakeSlice(dstRv.Type(), 1, 1)
+	m.pushIndex(0)
+	err := m.mapValue(slice.Index(0), srcRv)
+	m.popPath()
+	if err != nil {
+		return err
+	}
+	dstRv.Set(slice)
+	return nil
+}
+
+// ==================================== Filtering =============================
+
+// AddFilterFunc adds a named predicate for the filter tag. Source elements
+// the predicate returns false for are dropped. Pointer elements are dereferenced
+// if the predicate takes values, nil elements are dropped then.
+//
+// Panics if f is not a valid predicate
+// Overwrites previous functions with the same name
+func (m *Mapper) AddFilterFunc(name string, f interface{}) {
+	ft := reflect.TypeOf(f)
+	if ft.NumIn() != 1 || ft.NumOut() != 1 || ft.Out(0).Kind() != reflect.Bool {
+		panic("Bad filter function")
+	}
+
+	m.updateRegistry(func(r *registry) {
+		if len(r.filterFunc) == 0 {
+			r.filterFunc = make(map[string]filterFuncClosure)
+		}
+
+		fv := reflect.ValueOf(f)
+		inType := ft.In(0)
+		r.filterFunc[name] = func(elem reflect.Value) (bool, error) {
+			for !elem.Type().AssignableTo(inType) && elem.Kind() == reflect.Ptr {
+				if elem.IsNil() {
+					return false, nil
+				}
+				elem = elem.Elem()
+			}
+			if !elem.Type().AssignableTo(inType) {
+				return false, TagError{Tag: "filter=" + name, Type: elem.Type(), Reason: "filter takes " + inType.String()}
+			}
+			return fv.Call([]reflect.Value{elem})[0].Bool(), nil
+		}
+	})
+}
+
+// Collect elements of a slice, a map or a map of slices (flattening it)
+// Returns false if the value is not a collection
+func collectElements(srcRv reflect.Value) ([]reflect.Value, bool) {
+	var elems []reflect.Value
+	switch {
+	case srcRv.Kind() == reflect.Slice || srcRv.Kind() == reflect.Array:
+		for i := 0; i < srcRv.Len(); i++ {
+			elems = append(elems, srcRv.Index(i))
+		}
+	case srcRv.Kind() == reflect.Map && srcRv.Type().Elem().Kind() == reflect.Slice:
+		mapIt := srcRv.MapRange()
+		for mapIt.Next() {
+			for i := 0; i < mapIt.Value().Len(); i++ {
+				elems = append(elems, mapIt.Value().Index(i))
+			}
+		}
+	case srcRv.Kind() == reflect.Map:
+		mapIt := srcRv.MapRange()
+		for mapIt.Next() {
+			elems = append(elems, mapIt.Value())
+		}
+	default:
+		return nil, false
+	}
+	return elems, true
+}
+
+// Drop elements rejected by a named filter function
+func (m *mapping) filterElements(elems []reflect.Value, dstType reflect.Type, name string) ([]reflect.Value, error) {
+	filter, ok := m.filterFunc[name]
+	if !ok {
+		return nil, TagError{Tag: "filter=" + name, Type: dstType, Reason: "no filter function registered"}
+	}
+	kept := elems[:0]
+	for _, elem := range elems {
+		keep, err := filter(elem)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			kept = append(kept, elem)
+		}
+	}
+	return kept, nil
+}
+
+// Drop elements with duplicate keyField values, keeping the first ones
+func uniqueElements(elems []reflect.Value, keyField string) ([]reflect.Value, error) {
+	seen := make(map[interface{}]struct{}, len(elems))
+	unique := elems[:0]
+	for _, elem := range elems {
+		if elem.Kind() == reflect.Ptr && elem.IsNil() {
+			continue
+		}
+		key, ok := findStructField(elem, keyField)
+		if !ok {
+			return nil, FieldNotFoundError{Type: elem.Type(), Field: keyField}
+		}
+		if !isHashable(key) {
+			return nil, TagError{Tag: "unique", Type: key.Type(), Reason: "key is not hashable"}
+		}
+		if _, ok := seen[key.Interface()]; ok {
+			continue
+		}
+		seen[key.Interface()] = struct{}{}
+		unique = append(unique, elem)
+	}
+	return unique, nil
+}
+
+// Map a slice, a map or a map of slices (flattening it) to a slice,
+// dropping elements by the filter and unique tags
+// Panics if dst is not a slice
+func (m *mapping) mapElements(dstRv, srcRv reflect.Value, tags fieldTags) error {
+	for srcRv.Kind() == reflect.Ptr {
+		if srcRv.IsNil() {
+			return m.mapNil(dstRv, srcRv)
+		}
+		srcRv = srcRv.Elem()
+	}
+
+	elems, ok := collectElements(srcRv)
+	if !ok {
+		return NoValidMappingError{
+			ToType:   dstRv.Type(),
+			FromType: srcRv.Type(),
+		}
+	}
+
+	var err error
+	for _, name := range tags.filter {
+		if elems, err = m.filterElements(elems, dstRv.Type(), name); err != nil {
+			return err
+		}
+	}
+	if tags.unique != "" {
+		if elems, err = uniqueElements(elems, tags.unique); err != nil {
+			return err
+		}
+	}
+
+	dstRv.Set(reflect.MakeSlice(dstRv.Type(), len(elems), len(elems)))
+	for i, elem := range elems {
+		m.pushIndex(i)
+		err := m.mapValue(dstRv.Index(i), elem)
+		m.popPath()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ==================================== Sorting ===============================
+
+// AddLessFunc adds a less function used for sorting slices of its argument type
+// with the sort tag without a key
+//
+// Panics if f is not a valid less function
+// Overwrites previous functions with the same type
+func (m *Mapper) AddLessFunc(f interface{}) {
+	ft := reflect.TypeOf(f)
+	if ft.NumIn() != 2 || ft.In(0) != ft.In(1) || ft.NumOut() != 1 || ft.Out(0).Kind() != reflect.Bool {
+		panic("Bad less function")
+	}
+
+	m.updateRegistry(func(r *registry) {
+		if len(r.lessFunc) == 0 {
+			r.lessFunc = make(map[reflect.Type]lessFuncClosure)
+		}
+
+		fv := reflect.ValueOf(f)
+		r.lessFunc[ft.In(0)] = func(a, b reflect.Value) bool {
+			return fv.Call([]reflect.Value{a, b})[0].Bool()
+		}
+	})
+}
+
+// Compare two values of the same type
+// Returns false if the values are not ordered
+func compareValues(a, b reflect.Value) (int, bool) {
+	if a.Type() == timeRfType {
+		at, bt := a.Interface().(time.Time), b.Interface().(time.Time)
+		switch {
+		case at.Before(bt):
+			return -1, true
+		case at.After(bt):
+			return 1, true
+		}
+		return 0, true
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int() < b.Int(), a.Int() > b.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float() < b.Float(), a.Float() > b.Float()), true
+	case reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case reflect.Bool:
+		return compareOrdered(!a.Bool() && b.Bool(), a.Bool() && !b.Bool()), true
+	case reflect.Ptr:
+		// nil pointers go first
+		switch {
+		case a.IsNil() && b.IsNil():
+			return 0, true
+		case a.IsNil():
+			return -1, true
+		case b.IsNil():
+			return 1, true
+		}
+		return compareValues(a.Elem(), b.Elem())
+	}
+	return 0, false
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// Sort a mapped slice by a field of its elements or a registered less function.
+// Keys prefixed with - sort in descending order.
+func (m *mapping) sortSlice(rv reflect.Value, key string) error {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return TagError{Tag: "sort", Type: rv.Type(), Reason: "not a slice"}
+	}
+	elemType := rv.Type().Elem()
+
+	// sort by registered less function
+	if key == "" {
+		less, ok := m.lessFunc[elemType]
+		if !ok {
+			return TagError{Tag: "sort", Type: rv.Type(), Reason: "no less function registered"}
+		}
+		sort.SliceStable(rv.Interface(), func(i, j int) bool {
+			return less(rv.Index(i), rv.Index(j))
+		})
+		return nil
+	}
+
+	// sort by field
+	descending := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	keys := make([]reflect.Value, rv.Len())
+	for i := range keys {
+		field, ok := findStructField(rv.Index(i), key)
+		if !ok && !(rv.Index(i).Kind() == reflect.Ptr && rv.Index(i).IsNil()) {
+			return FieldNotFoundError{Type: elemType, Field: key}
+		}
+		keys[i] = field
+	}
+
+	var sortErr error
+	indices := make([]int, len(keys))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		a, b := keys[indices[i]], keys[indices[j]]
+		// elements without keys go last
+		if !a.IsValid() || !b.IsValid() {
+			return a.IsValid() && !b.IsValid()
+		}
+		cmp, ok := compareValues(a, b)
+		if !ok {
+			sortErr = TagError{Tag: "sort", Type: a.Type(), Reason: "values are not ordered"}
+		}
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+
+	// reorder elements by sorted indices
+	sorted := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+	for i, idx := range indices {
+		sorted.Index(i).Set(rv.Index(idx))
+	}
+	reflect.Copy(rv, sorted)
+	return nil
+}
+
+// ==================================== Map key order =========================
+
+// Map the keys of a map field of the same struct into a slice, in the order of the
+// source field of the map if it is an ordered map, or in sorted order otherwise.
+// Go maps have no order, so this keeps the order of mapped maps stable.
+func (m *mapping) mapKeysOf(dstRv, srcRv reflect.Value, fields structFieldMap, name string) error {
+	if source, ok := collectStructFields(srcRv).get(name); ok {
+		sourceRv := source.value
+		for sourceRv.Kind() == reflect.Ptr && !sourceRv.IsNil() {
+			sourceRv = sourceRv.Elem()
+		}
+		if isOrderedMap(sourceRv.Type()) {
+			return m.mapValue(dstRv, orderedMapKeys(sourceRv))
+		}
+	}
+
+	field, ok := fields.get(name)
+	if !ok {
+		return FieldNotFoundError{Type: dstRv.Type(), Field: name}
+	}
+	mapRv := field.value
+	if mapRv.Kind() != reflect.Map {
+		return TagError{Tag: "keys", Type: mapRv.Type(), Reason: "not a map"}
+	}
+
+	keys := mapRv.MapKeys()
+	var sortErr error
+	sort.Slice(keys, func(i, j int) bool {
+		cmp, ok := compareValues(keys[i], keys[j])
+		if !ok {
+			sortErr = TagError{Tag: "keys", Type: mapRv.Type(), Reason: "keys are not ordered"}
+		}
+		return cmp < 0
+	})
+	if sortErr != nil {
+		return sortErr
+	}
+
+	keySlice := reflect.MakeSlice(reflect.SliceOf(mapRv.Type().Key()), len(keys), len(keys))
+	for i, key := range keys {
+		keySlice.Index(i).Set(key)
+	}
+	return m.mapValue(dstRv, keySlice)
+}
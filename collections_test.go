@@ -0,0 +1,394 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Build a map from a slice keyed by a field of its elements
+func TestTagIndex(t *testing.T) {
+	type ProductDto struct {
+		Country string
+		Price   int
+	}
+	var out struct {
+		Products map[string]ProductDto `dto:"index=Name"`
+	}
+	from := ShoppingCart{Products: commonProducts}
+
+	err := Map(&out, from)
+	assert.Nil(t, err)
+
+	assert.Equal(t, len(commonProducts), len(out.Products))
+	for _, product := range commonProducts {
+		assert.Equal(t, product.Country, out.Products[product.Name].Country)
+		assert.Equal(t, int(product.Price), out.Products[product.Name].Price)
+	}
+}
+
+// Index elements behind pointers and skip nil elements
+func TestTagIndexPointers(t *testing.T) {
+	var out struct {
+		Products map[string]*Product `dto:"index=Name"`
+	}
+	from := struct {
+		Products []*Product
+	}{Products: []*Product{&commonProducts[0], nil, &commonProducts[1]}}
+
+	err := Map(&out, from)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(out.Products))
+	assert.Equal(t, commonProducts[1], *out.Products[commonProducts[1].Name])
+}
+
+// Fail on missing index fields
+func TestTagIndexMissingField(t *testing.T) {
+	var out struct {
+		Products map[string]Product `dto:"index=Id"`
+	}
+	err := Map(&out, ShoppingCart{Products: commonProducts})
+	assert.ErrorIs(t, err, FieldNotFoundError{
+		Type:  reflect.TypeOf(Product{}),
+		Field: "Id",
+	})
+}
+
+// Fail on index tags of fields that are not maps
+func TestTagIndexNotMap(t *testing.T) {
+	var out struct {
+		Products []Product `dto:"index=Name"`
+	}
+	err := Map(&out, ShoppingCart{Products: commonProducts})
+	assert.Equal(t, TagError{Tag: "index", Type: reflect.TypeOf([]Product{}), Reason: "not a map"}, err)
+
+	mapper := Mapper{}
+	mapper.RegisterPair(&out, ShoppingCart{})
+	var problems ConfigError
+	assert.ErrorAs(t, mapper.Validate(), &problems)
+	assert.Equal(t, err, problems[0].Err)
+}
+
+// Group a slice into a map of slices by a field of its elements
+func TestTagGroupBy(t *testing.T) {
+	var out struct {
+		Products map[string][]struct {
+			Name string
+		} `dto:"groupby=Country"`
+	}
+	err := Map(&out, ShoppingCart{Products: commonProducts})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(out.Products))
+	assert.Equal(t, 2, len(out.Products["US"]))
+	assert.Equal(t, commonProducts[0].Name, out.Products["US"][0].Name)
+	assert.Equal(t, commonProducts[3].Name, out.Products["US"][1].Name)
+	assert.Equal(t, commonProducts[1].Name, out.Products["UK"][0].Name)
+}
+
+// Fail on groupby tags of fields that are not maps of slices
+func TestTagGroupByNotGroups(t *testing.T) {
+	var out struct {
+		Products map[string]Product `dto:"groupby=Country"`
+	}
+	err := Map(&out, ShoppingCart{Products: commonProducts})
+	assert.Equal(t, TagError{Tag: "groupby", Type: reflect.TypeOf(map[string]Product{}), Reason: "not a map of slices"}, err)
+
+	mapper := Mapper{}
+	mapper.RegisterPair(&out, ShoppingCart{})
+	var problems ConfigError
+	assert.ErrorAs(t, mapper.Validate(), &problems)
+	assert.Equal(t, err, problems[0].Err)
+}
+
+// Sort mapped slices by a field of their elements
+func TestTagSort(t *testing.T) {
+	type ProductDto struct {
+		Name  string
+		Price float32
+	}
+	var out struct {
+		ByName  []ProductDto  `dto:"sort=Name"`
+		ByPrice []*ProductDto `dto:"sort=-Price"`
+	}
+	from := struct {
+		ByName  []Product
+		ByPrice []Product
+	}{commonProducts, commonProducts}
+
+	err := Map(&out, from)
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, p := range out.ByName {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"Bowtie", "Hat", "Shirt", "Shoes"}, names)
+
+	prices := []float32{}
+	for _, p := range out.ByPrice {
+		prices = append(prices, p.Price)
+	}
+	assert.Equal(t, []float32{19.7, 17.3, 9.4, 5.1}, prices)
+}
+
+// Sort mapped slices with a registered less function
+func TestTagSortLessFunc(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	var out struct {
+		Products []ProductDto `dto:"sort"`
+	}
+
+	m := Mapper{}
+	m.AddLessFunc(func(a, b ProductDto) bool {
+		return len(a.Name) < len(b.Name)
+	})
+	err := m.Map(&out, ShoppingCart{Products: commonProducts})
+	assert.Nil(t, err)
+	assert.Equal(t, "Hat", out.Products[0].Name)
+	assert.Equal(t, "Bowtie", out.Products[3].Name)
+
+	err = Map(&out, ShoppingCart{Products: commonProducts})
+	assert.ErrorAs(t, err, &TagError{})
+}
+
+// De-duplicate elements when flattening a map of slices
+func TestTagUnique(t *testing.T) {
+	var out struct {
+		Products []struct {
+			Name string
+		} `dto:"unique=Name,sort=Name"`
+	}
+	from := TaggedShoppingCart{
+		Products: map[string][]Product{
+			"Sale": {commonProducts[0], commonProducts[1]},
+			"New":  {commonProducts[1], commonProducts[2]},
+		},
+	}
+
+	err := Map(&out, from)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(out.Products))
+	assert.Equal(t, "Hat", out.Products[0].Name)
+	assert.Equal(t, "Shirt", out.Products[1].Name)
+	assert.Equal(t, "Shoes", out.Products[2].Name)
+}
+
+// Fail on unique keys that can't be hashed, including interfaces holding slices
+func TestTagUniqueNotHashable(t *testing.T) {
+	type Tagged struct {
+		Key interface{}
+	}
+	var out struct {
+		Items []Tagged `dto:"unique=Key"`
+	}
+	err := Map(&out, struct{ Items []Tagged }{[]Tagged{{"a"}, {[]int{1}}}})
+	assert.Equal(t, TagError{Tag: "unique", Type: reflect.TypeOf((*interface{})(nil)).Elem(), Reason: "key is not hashable"}, err)
+}
+
+// Map slices to single values by tags and policy
+func TestUnwrapSlice(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	{
+		var out struct {
+			Product *ProductDto `dto:"single"`
+			First   ProductDto  `dto:"first"`
+		}
+		from := struct {
+			Product []Product
+			First   []*Product
+		}{commonProducts[:1], []*Product{&commonProducts[1], &commonProducts[2]}}
+		err := Map(&out, from)
+		assert.Nil(t, err)
+		assert.Equal(t, commonProducts[0].Name, out.Product.Name)
+		assert.Equal(t, commonProducts[1].Name, out.First.Name)
+	}
+	{
+		var out struct {
+			Product ProductDto
+		}
+		from := struct {
+			Product []Product
+		}{commonProducts[:2]}
+		err := Map(&out, from)
+		assert.ErrorAs(t, err, &NoValidMappingError{})
+
+		m := NewMapper(WithUnwrapPolicy(UnwrapSingle))
+		err = m.Map(&out, from)
+		assert.ErrorAs(t, err, &AmbiguousSliceError{})
+
+		from.Product = nil
+		err = m.Map(&out, from)
+		assert.Nil(t, err)
+		assert.Zero(t, out.Product)
+	}
+}
+
+// Map single values to single element slices by tags and option
+func TestWrapValue(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	from := struct {
+		Product  Product
+		Products *Product
+		Tags     string
+	}{commonProducts[0], &commonProducts[1], "new"}
+	{
+		var out struct {
+			Product []ProductDto `dto:"wrap"`
+		}
+		err := Map(&out, from)
+		assert.Nil(t, err)
+		assert.Equal(t, []ProductDto{{commonProducts[0].Name}}, out.Product)
+	}
+	{
+		var out struct {
+			Product []ProductDto
+		}
+		err := Map(&out, from)
+		assert.ErrorAs(t, err, &NoValidMappingError{})
+	}
+	{
+		var out struct {
+			Product  []ProductDto
+			Products []*ProductDto
+			Tags     []string
+		}
+		err := NewMapper(WithWrapValues(true)).Map(&out, from)
+		assert.Nil(t, err)
+		assert.Equal(t, []ProductDto{{commonProducts[0].Name}}, out.Product)
+		assert.Equal(t, []*ProductDto{{commonProducts[1].Name}}, out.Products)
+		assert.Equal(t, []string{"new"}, out.Tags)
+	}
+	{
+		// arrays are mapped element by element instead of being wrapped
+		var out struct {
+			L []int
+			W []int `dto:"wrap"`
+		}
+		err := NewMapper(WithWrapValues(true)).Map(&out, struct{ L, W [2]int }{[2]int{1, 2}, [2]int{3, 4}})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, out.L)
+		assert.Equal(t, []int{3, 4}, out.W)
+	}
+}
+
+// Drop source elements rejected by registered filter functions
+func TestTagFilter(t *testing.T) {
+	var out struct {
+		Products []struct {
+			Name string
+		} `dto:"filter=cheap,filter=european"`
+		Cheap []*Product `dto:"filter=cheap"`
+	}
+	from := struct {
+		Products []*Product
+		Cheap    []Product
+	}{
+		Products: []*Product{&commonProducts[0], nil, &commonProducts[1], &commonProducts[3]},
+		Cheap:    commonProducts,
+	}
+
+	m := Mapper{}
+	m.AddFilterFunc("cheap", func(p Product) bool {
+		return p.Price < 18
+	})
+	m.AddFilterFunc("european", func(p *Product) bool {
+		return p != nil && p.Country != "US"
+	})
+
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(out.Products))
+	assert.Equal(t, "Shoes", out.Products[0].Name)
+	assert.Equal(t, 3, len(out.Cheap))
+
+	err = Map(&out, from)
+	assert.ErrorAs(t, err, &TagError{})
+}
+
+// Ordered map with insertion order, like of third-party libraries
+type orderedStock struct {
+	keys   []string
+	values map[string]int
+}
+
+func (st *orderedStock) Set(key string, value int) {
+	if st.values == nil {
+		st.values = make(map[string]int)
+	}
+	if _, ok := st.values[key]; !ok {
+		st.keys = append(st.keys, key)
+	}
+	st.values[key] = value
+}
+
+func (st orderedStock) Keys() []string {
+	return st.keys
+}
+
+func (st orderedStock) Get(key string) (int, bool) {
+	value, ok := st.values[key]
+	return value, ok
+}
+
+// Ordered maps are mapped to maps and keys tags keep their order
+func TestOrderedMaps(t *testing.T) {
+	var stock orderedStock
+	stock.Set("M", 3)
+	stock.Set("S", 1)
+	stock.Set("XL", 0)
+
+	var out struct {
+		Stock  map[string]int64
+		Sizes  []string `dto:"keys=Stock"`
+		Counts []int
+	}
+	err := Map(&out, struct{ Stock, Counts *orderedStock }{&stock, &stock})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"M": 3, "S": 1, "XL": 0}, out.Stock)
+	assert.Equal(t, []string{"M", "S", "XL"}, out.Sizes)
+	assert.Equal(t, []int{3, 1, 0}, out.Counts)
+
+	m := Mapper{}
+	m.RegisterPair(&out, struct{ Stock, Counts orderedStock }{})
+	assert.Nil(t, m.Validate())
+}
+
+// Keys of mapped maps are collected in sorted order with the keys tag
+func TestMapKeysOf(t *testing.T) {
+	type CatalogDto struct {
+		Countries []string `dto:"keys=ByCountry"`
+		ByCountry map[string][]Product
+		Years     []int64 `dto:"keys=ByYear"`
+		ByYear    map[int]string
+		Products  []string `dto:"keys=ByCountry"`
+	}
+	from := struct {
+		ByCountry map[string][]Product
+		ByYear    map[int]string
+		Products  []string
+	}{cartByCountries.Products, map[int]string{2021: "b", 2019: "a", 2020: "c"}, []string{"Shirt"}}
+
+	var out CatalogDto
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"America", "Europe"}, out.Countries)
+	assert.Equal(t, []int64{2019, 2020, 2021}, out.Years)
+	assert.Equal(t, []string{"Shirt"}, out.Products)
+
+	var outMissing struct {
+		Keys []string `dto:"keys=Values"`
+	}
+	err = Map(&outMissing, from)
+	assert.ErrorAs(t, err, &FieldNotFoundError{})
+}
@@ -0,0 +1,234 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConfigProblem describes a problem in the mapping configuration of a type pair
+type ConfigProblem struct {
+	ToType   reflect.Type
+	FromType reflect.Type
+	// Path of the destination field, like Products[*].Price
+	Path string
+	Err  error
+}
+
+func (cp ConfigProblem) Error() string {
+	if cp.Path == "" {
+		return fmt.Sprintf("Invalid mapping of %v to %v: %v", typeName(cp.FromType), typeName(cp.ToType), cp.Err)
+	}
+	return fmt.Sprintf("Invalid mapping of %v to %v at %v: %v", typeName(cp.FromType), typeName(cp.ToType), cp.Path, cp.Err)
+}
+
+func (cp ConfigProblem) Unwrap() error {
+	return cp.Err
+}
+
+// ConfigError is a report of all problems found by Validate
+type ConfigError []ConfigProblem
+
+func (ce ConfigError) Error() string {
+	msgs := make([]string, len(ce))
+	for i, cp := range ce {
+		msgs[i] = cp.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// RegisterPair registers the types of dst and src to be checked by Validate.
+// Pointers are removed (first layer only).
+func (m *Mapper) RegisterPair(dst, src interface{}) {
+	pair := typePair{dst: reflectValueRemovePtr(dst).Type(), src: reflectValueRemovePtr(src).Type()}
+	m.updateRegistry(func(r *registry) {
+		r.pairs = append(r.pairs, pair)
+	})
+}
+
+// Validate checks the configuration of all registered type pairs, so that
+// mistakes fail fast at startup instead of on the first Map call. It reports
+// unknown or malformed tags, missing filter and less functions, tags that refer
+// to missing fields and pairs that can't be mapped at all.
+//
+// Returns a ConfigError with all problems found, or nil
+func (m *Mapper) Validate() error {
+	var problems ConfigError
+	for _, pair := range m.loadRegistry().pairs {
+		check := coverageCheck{
+			mapping:     m.newMapping(),
+			visited:     make(map[typePair]bool),
+			validate:    true,
+			checkedTags: make(map[typePair]bool),
+		}
+		err := check.checkType(pair.dst, pair.src)
+		for _, problem := range check.problems {
+			problem.ToType, problem.FromType = pair.dst, pair.src
+			problems = append(problems, problem)
+		}
+		if err != nil {
+			problems = append(problems, ConfigProblem{ToType: pair.dst, FromType: pair.src, Path: check.errorPath, Err: err})
+		}
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+// ==================================== Tag checks ============================
+
+// Record a configuration problem at the current path
+func (cc *coverageCheck) addProblem(err error) {
+	cc.problems = append(cc.problems, ConfigProblem{Path: cc.pathString(), Err: err})
+}
+
+// Check the tags of a destination field of a struct mapped from srcType.
+// fromType is nil if the field has no source.
+func (cc *coverageCheck) checkTags(info structFieldInfo, toType, fromType, srcType reflect.Type,
+	fromFields map[string]structFieldInfo) {
+	tags := info.tags
+	if option, ok := unknownTagOption(info.rawTag.Get(structTag)); ok {
+		cc.addProblem(TagError{Tag: option, Type: toType, Reason: "unknown option"})
+	}
+
+	// constraints are checked against a zero value
+	if err := cc.validateField(reflect.New(derefType(toType)).Elem(), tags); err != nil {
+		cc.addProblem(err)
+	}
+	cc.violations = nil
+
+	// squashed structs are promoted, so only other types are left
+	if tags.squash {
+		cc.addProblem(TagError{Tag: "squash", Type: toType, Reason: "not a struct"})
+	}
+
+	// derived fields
+	if tags.keysOf != "" {
+		if source, ok := fromFields[tags.keysOf]; !ok {
+			cc.addProblem(FieldNotFoundError{Type: srcType, Field: tags.keysOf})
+		} else if sourceType := derefType(srcType.FieldByIndex(source.index).Type); sourceType.Kind() != reflect.Map &&
+			!isOrderedMap(sourceType) {
+			cc.addProblem(TagError{Tag: "keys", Type: sourceType, Reason: "not a map"})
+		}
+	}
+	if tags.format != "" {
+		if err := checkNumberFormat(toType, tags.format); err != nil {
+			cc.addProblem(err)
+		}
+	}
+	if tags.precision != "" {
+		if _, err := fieldPrecision(toType, tags.precision); err != nil {
+			cc.addProblem(err)
+		}
+	}
+	if tags.remain && !isRemainType(toType) {
+		cc.addProblem(TagError{Tag: "remain", Type: toType, Reason: "not a map with string keys"})
+	}
+	if tags.template != "" {
+		if _, err := fieldTemplate(toType, tags); err != nil {
+			cc.addProblem(err)
+		}
+	}
+	if tags.hasDefault {
+		if _, err := parseTagValue(toType, tags.defaultValue, false); err != nil {
+			cc.addProblem(err)
+		}
+	}
+	if tags.isConst {
+		if _, err := parseTagValue(toType, tags.constant, tags.constInt); err != nil {
+			cc.addProblem(err)
+		}
+	}
+	if tags.from != "" {
+		if _, ok := fromFieldInfo(srcType, tags.from); !ok {
+			cc.addProblem(FieldNotFoundError{Type: srcType, Field: tags.from})
+		}
+	}
+	if tags.present != "" {
+		if _, ok := fromFields[tags.present]; !ok {
+			cc.addProblem(FieldNotFoundError{Type: srcType, Field: tags.present})
+		}
+	}
+
+	// collections
+	if tags.sort {
+		cc.checkSortTag(toType, tags.sortKey)
+	}
+	if tags.index != "" && toType.Kind() != reflect.Map {
+		cc.addProblem(TagError{Tag: "index", Type: toType, Reason: "not a map"})
+	}
+	if tags.groupBy != "" && !isGroupsType(toType) {
+		cc.addProblem(TagError{Tag: "groupby", Type: toType, Reason: "not a map of slices"})
+	}
+	if tags.flags != "" || tags.flag != "" {
+		if err := checkFlagTypes(toType, fromType, tags); err != nil {
+			cc.addProblem(err)
+		} else if _, _, err := cc.findFlags(toType, tags); err != nil {
+			cc.addProblem(err)
+		}
+	}
+	for _, name := range tags.filter {
+		if _, ok := cc.filterFunc[name]; !ok {
+			cc.addProblem(TagError{Tag: "filter=" + name, Type: toType, Reason: "no filter function registered"})
+		}
+	}
+	if fromType == nil {
+		return
+	}
+	if tags.json && !isJSONPair(toType, fromType) {
+		cc.addProblem(TagError{Tag: "json", Type: toType, Reason: "neither type is a json.RawMessage"})
+	}
+	for tag, keyField := range map[string]string{"index": tags.index, "groupby": tags.groupBy, "unique": tags.unique} {
+		if keyField != "" {
+			cc.checkElemField(tag, fromType, keyField)
+		}
+	}
+}
+
+// Check that the sort tag of a slice has a key field or a less function
+func (cc *coverageCheck) checkSortTag(toType reflect.Type, key string) {
+	sliceType := derefType(toType)
+	if sliceType.Kind() != reflect.Slice {
+		cc.addProblem(TagError{Tag: "sort", Type: toType, Reason: "not a slice"})
+		return
+	}
+	if key == "" {
+		if _, ok := cc.lessFunc[sliceType.Elem()]; !ok {
+			cc.addProblem(TagError{Tag: "sort", Type: sliceType, Reason: "no less function registered"})
+		}
+		return
+	}
+	cc.checkElemField("sort", sliceType, strings.TrimPrefix(key, "-"))
+}
+
+// Check that elements of a collection type have a field
+func (cc *coverageCheck) checkElemField(tag string, collType reflect.Type, field string) {
+	collType = derefType(collType)
+	if collType.Kind() != reflect.Slice && collType.Kind() != reflect.Array && collType.Kind() != reflect.Map {
+		cc.addProblem(TagError{Tag: tag, Type: collType, Reason: "not a collection"})
+		return
+	}
+	elemType := derefType(collType.Elem())
+	if elemType.Kind() == reflect.Map || elemType.Kind() == reflect.Slice {
+		elemType = derefType(elemType.Elem())
+	}
+	if elemType.Kind() != reflect.Struct {
+		cc.addProblem(TagError{Tag: tag, Type: elemType, Reason: "elements are not structs"})
+		return
+	}
+	for _, info := range structFieldInfos(elemType) {
+		if info.key == field {
+			return
+		}
+	}
+	cc.addProblem(FieldNotFoundError{Type: elemType, Field: field})
+}
+
+// Remove all pointers from a type
+func derefType(rfType reflect.Type) reflect.Type {
+	for rfType.Kind() == reflect.Ptr {
+		rfType = rfType.Elem()
+	}
+	return rfType
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Valid configurations pass
+func TestValidate(t *testing.T) {
+	type ProductDto struct {
+		Name  string `dto:"min=1"`
+		Price float32
+	}
+	type CartDto struct {
+		Products []ProductDto          `dto:"sort=Price,filter=available"`
+		ByName   map[string]ProductDto `dto:"index=Name"`
+	}
+	type Cart struct {
+		Products []Product
+		ByName   []Product
+	}
+
+	mapper := Mapper{}
+	mapper.AddFilterFunc("available", func(p Product) bool { return p.Country != "" })
+	mapper.RegisterPair(&CartDto{}, Cart{})
+	assert.Nil(t, mapper.Validate())
+	assert.Nil(t, (&Mapper{}).Validate())
+}
+
+// All problems are reported at once
+func TestValidateProblems(t *testing.T) {
+	type ProductDto struct {
+		Name  string  `dto:"oneof=a b,mni=1"`
+		Price float32 `dto:"max=cheap"`
+	}
+	type CartDto struct {
+		Products []ProductDto          `dto:"sort,filter=available"`
+		ByName   map[string]ProductDto `dto:"index=Title"`
+		Sizes    []string              `dto:"keys=Stock"`
+		Owner    struct{ ID int }
+	}
+	type Cart struct {
+		Products []Product
+		ByName   []Product
+		Owner    struct{ ID []int }
+	}
+
+	mapper := Mapper{}
+	mapper.RegisterPair(&CartDto{}, Cart{})
+	err := mapper.Validate()
+
+	var problems ConfigError
+	assert.True(t, errors.As(err, &problems))
+	paths := make(map[string][]error)
+	for _, problem := range problems {
+		paths[problem.Path] = append(paths[problem.Path], problem.Err)
+	}
+
+	assert.Len(t, paths["Products[*].Name"], 1)
+	assert.Equal(t, TagError{Tag: "mni", Type: stringRfType, Reason: "unknown option"}, paths["Products[*].Name"][0])
+	assert.Len(t, paths["Products[*].Price"], 1)
+	assert.Len(t, paths["Products"], 2)
+	assert.Equal(t, []error{FieldNotFoundError{Type: reflectValueRemovePtr(Product{}).Type(), Field: "Title"}},
+		paths["ByName"])
+	assert.Len(t, paths["Sizes"], 1)
+	assert.True(t, errors.As(paths["Owner.ID"][0], &NoValidMappingError{}))
+	assert.Len(t, problems, 7)
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Parse the value of a tag like const or default for a destination field.
+// Values are parsed by the kind of the field, so const=42 works for int fields.
+// Integers are parsed regardless of the field if asInt is set.
+func parseTagValue(dstType reflect.Type, text string, asInt bool) (reflect.Value, error) {
+	kindType := derefType(dstType)
+	kind := kindType.Kind()
+	if asInt {
+		kind = reflect.Int64
+	}
+	var value interface{}
+	var err error
+	switch kind {
+	case reflect.Bool:
+		value, err = strconv.ParseBool(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(text, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err = strconv.ParseUint(text, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(text, 64)
+	default:
+		value = text
+	}
+	if err != nil {
+		return reflect.Value{}, ParseError{Value: text, Type: kindType}
+	}
+	return reflect.ValueOf(value), nil
+}
+
+// Set a destination field to the value of its const or constInt tag
+func (m *mapping) mapConst(dstRv reflect.Value, tags fieldTags) error {
+	value, err := parseTagValue(dstRv.Type(), tags.constant, tags.constInt)
+	if err != nil {
+		return err
+	}
+	return m.mapValue(dstRv, value)
+}
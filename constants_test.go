@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Constant fields are set to their tag value, even if the source has them
+func TestConstTags(t *testing.T) {
+	type Kind string
+	type EventDto struct {
+		APIVersion string  `dto:"const=v1"`
+		Kind       Kind    `dto:"const=created"`
+		Schema     int     `dto:"constInt=42"`
+		Weight     float64 `dto:"const=0.5"`
+		Public     *bool   `dto:"const=true"`
+		Name       string
+	}
+	type Event struct {
+		APIVersion string
+		Name       string
+	}
+	dto := EventDto{}
+	err := Map(&dto, Event{APIVersion: "v0", Name: "signup"})
+	assert.Nil(t, err)
+	public := true
+	assert.Equal(t, EventDto{APIVersion: "v1", Kind: "created", Schema: 42, Weight: 0.5, Public: &public, Name: "signup"}, dto)
+
+	// they are not reported as missing
+	mapper := Mapper{}
+	missing, err := mapper.CheckCoverage(EventDto{}, Event{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
+
+// Values that don't match the field are reported
+func TestConstTagErrors(t *testing.T) {
+	type Dto struct {
+		Version int `dto:"const=v1"`
+	}
+	err := Map(&Dto{}, struct{}{})
+	var pe ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "v1", pe.Value)
+
+	mapper := Mapper{}
+	mapper.RegisterPair(Dto{}, struct{}{})
+	assert.NotNil(t, mapper.Validate())
+}
+
+// Default values are used for missing and zero sources
+func TestDefaultTag(t *testing.T) {
+	type User struct {
+		Name  string
+		Score int
+	}
+	type UserDto struct {
+		Name    string  `dto:"default=unknown"`
+		Score   int     `dto:"default=10"`
+		Active  bool    `dto:"default=true"`
+		Country *string `dto:"default=DE"`
+	}
+	dto := UserDto{}
+	err := Map(&dto, User{})
+	assert.Nil(t, err)
+	country := "DE"
+	assert.Equal(t, UserDto{Name: "unknown", Score: 10, Active: true, Country: &country}, dto)
+
+	dto = UserDto{}
+	err = Map(&dto, User{Name: "Bob", Score: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, UserDto{Name: "Bob", Score: 3, Active: true, Country: &country}, dto)
+
+	// they are not reported as missing
+	mapper := Mapper{}
+	missing, err := mapper.CheckCoverage(UserDto{}, User{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+
+	// invalid values are reported
+	type BadDto struct {
+		Score int `dto:"default=many"`
+	}
+	err = Map(&BadDto{}, User{})
+	assert.Equal(t, ParseError{Value: "many", Type: reflect.TypeOf(0)}, err)
+}
@@ -0,0 +1,96 @@
+package dto
+
+import "reflect"
+
+// ConvFuncOption annotates a conversion function when it is added
+type ConvFuncOption int
+
+const (
+	// Cacheable marks a conversion function whose result depends only on its argument.
+	// Results are cached per Map call by argument value, so identical values in
+	// large collections, like country codes, are converted only once.
+	// Cached results are shared between destinations, so they should not be mutated.
+	// Arguments that are not comparable are not cached.
+	// Functions that take FieldInfo depend on the field they map, so adding them as Cacheable panics.
+	Cacheable ConvFuncOption = iota + 1
+	// Pure marks a conversion function without side effects, whose result depends only on its argument.
+	// Pure implies Cacheable, which is the only effect it has on mapping: calls are never
+	// parallelized or reordered, so impure functions are neither detected nor reported.
+	// Functions are considered impure unless marked, Converters reports the annotation.
+	Pure
+)
+
+// Maximum number of cached conversion results per Map call
+const convCacheSize = 4096
+
+// Cache key of a conversion result
+type convCacheKey struct {
+	fn    *byte
+	value interface{}
+}
+
+// Check if conversion function options contain an option
+func hasConvFuncOption(opts []ConvFuncOption, opt ConvFuncOption) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe a conversion function with its options
+func convFuncInfo(inType, outType reflect.Type, name string, opts []ConvFuncOption) ConverterInfo {
+	pure := hasConvFuncOption(opts, Pure)
+	return ConverterInfo{
+		From:      inType,
+		To:        outType,
+		Name:      name,
+		Cacheable: pure || hasConvFuncOption(opts, Cacheable),
+		Pure:      pure,
+	}
+}
+
+// Check if a conversion function takes field info
+func takesFieldInfo(rt reflect.Type) bool {
+	for i := 1; i < rt.NumIn(); i++ {
+		if rt.In(i) == fieldInfoRfType {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply conversion function options to the closure of f
+func applyConvFuncOptions(f interface{}, closure convertFuncClosure, opts []ConvFuncOption) convertFuncClosure {
+	if hasConvFuncOption(opts, Cacheable) || hasConvFuncOption(opts, Pure) {
+		if takesFieldInfo(reflect.TypeOf(f)) {
+			panic("Cacheable conversion function can't take field info")
+		}
+		closure = cacheConvFunc(closure)
+	}
+	return closure
+}
+
+// Wrap a conversion closure, so that its results are cached per Map call
+func cacheConvFunc(closure convertFuncClosure) convertFuncClosure {
+	id := new(byte)
+	return func(from reflect.Value, m *mapping) (reflect.Value, error) {
+		if !from.CanInterface() || !isHashable(from) {
+			return closure(from, m)
+		}
+		key := convCacheKey{fn: id, value: from.Interface()}
+		if out, ok := m.convCache[key]; ok {
+			return out, nil
+		}
+		out, err := closure(from, m)
+		if err != nil || len(m.convCache) >= convCacheSize {
+			return out, err
+		}
+		if m.convCache == nil {
+			m.convCache = make(map[convCacheKey]reflect.Value)
+		}
+		m.convCache[key] = out
+		return out, nil
+	}
+}
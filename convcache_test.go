@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Results of cacheable conversion functions are cached per Map call
+func TestCacheableConvFunc(t *testing.T) {
+	type CountryCode string
+	type ProductDto struct {
+		Country CountryCode
+	}
+
+	calls := 0
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(name string) CountryCode {
+		calls++
+		return CountryCode(strings.ToUpper(name[:2]))
+	}, Cacheable)
+
+	products := []Product{{Country: "germany"}, {Country: "france"}, {Country: "germany"}, {Country: "germany"}}
+	var out []ProductDto
+	err := mapper.Map(&out, products)
+	assert.Nil(t, err)
+	assert.Equal(t, []ProductDto{{"GE"}, {"FR"}, {"GE"}, {"GE"}}, out)
+	assert.Equal(t, 2, calls)
+
+	// the cache doesn't outlive calls
+	err = mapper.Map(&out, products)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, calls)
+
+	assert.True(t, mapper.Converters()[0].Cacheable)
+}
+
+// Pure conversion functions are cached
+func TestPureConvFunc(t *testing.T) {
+	calls := 0
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(price float32) string {
+		calls++
+		return "expensive"
+	}, Pure)
+	mapper.AddConvFunc(func(name string) []byte { return []byte(name) })
+
+	var out []struct{ Price string }
+	err := mapper.Map(&out, []Product{{Price: 10}, {Price: 10}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, calls)
+
+	converters := mapper.Converters()
+	assert.True(t, converters[0].Pure)
+	assert.True(t, converters[0].Cacheable)
+	assert.False(t, converters[1].Pure)
+}
+
+// Conversion functions that depend on field info can't be cached
+func TestCacheableConvFuncFieldInfo(t *testing.T) {
+	mapper := Mapper{}
+	assert.Panics(t, func() {
+		mapper.AddConvFunc(func(v float64, field FieldInfo) string { return field.Name }, Cacheable)
+	})
+	assert.Panics(t, func() {
+		mapper.AddScopedConvFunc("api", func(v float64, field FieldInfo) string { return field.Name }, Pure)
+	})
+
+	// without the annotation, every field gets its own result
+	mapper.AddConvFunc(func(v float64, field FieldInfo) string {
+		return strconv.FormatFloat(v, 'f', len(field.Name), 64)
+	})
+	var out struct{ A, Abc string }
+	err := mapper.Map(&out, struct{ A, Abc float64 }{1.5, 1.5})
+	assert.Nil(t, err)
+	assert.Equal(t, "1.5", out.A)
+	assert.Equal(t, "1.500", out.Abc)
+	assert.False(t, mapper.Converters()[0].Cacheable)
+}
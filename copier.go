@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Struct tag of jinzhu/copier
+const copierTag = "copier"
+
+// Parse a copier struct tag, like copier:"Name", copier:"-" or copier:"Name,must".
+// Returns the name to match the field by, if any, and whether the field is ignored.
+// Options of copier that don't affect matching are skipped.
+func parseCopierTag(tag string) (string, bool) {
+	var name string
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "-":
+			return "", true
+		case option != "" && unicode.IsUpper(rune(option[0])):
+			name = option
+		}
+	}
+	return name, false
+}
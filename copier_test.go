@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Fields are matched by copier tags
+func TestCopierTags(t *testing.T) {
+	type UserDto struct {
+		FullName string `copier:"Name"`
+		Email    string `copier:"-"`
+		Age      int    `copier:"must,nopanic"`
+	}
+	type User struct {
+		Name     string
+		Email    string
+		Years    int `copier:"Age"`
+		Password string
+	}
+
+	out := UserDto{Email: "kept"}
+	err := Map(&out, User{Name: "Ann", Email: "ann@example.com", Years: 30})
+	assert.Nil(t, err)
+	assert.Equal(t, UserDto{FullName: "Ann", Email: "kept", Age: 30}, out)
+
+	// paths use field names
+	var paths []string
+	mapper := Mapper{}
+	mapper.AddInspectFunc(func(name *string, field FieldInfo) {
+		paths = append(paths, field.Path)
+	})
+	err = mapper.Map(&out, User{Name: "Ann"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"FullName"}, paths)
+}
+
+// Copier tags are parsed like by copier
+func TestParseCopierTag(t *testing.T) {
+	for tag, expected := range map[string]struct {
+		name   string
+		ignore bool
+	}{
+		"":              {},
+		"-":             {ignore: true},
+		"Name":          {name: "Name"},
+		"Name, must":    {name: "Name"},
+		"must,override": {},
+	} {
+		name, ignore := parseCopierTag(tag)
+		assert.Equal(t, expected.name, name, tag)
+		assert.Equal(t, expected.ignore, ignore, tag)
+	}
+}
@@ -0,0 +1,310 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Type pair visited by coverage checks
+type typePair struct {
+	dst reflect.Type
+	src reflect.Type
+}
+
+// State of a coverage check
+type coverageCheck struct {
+	*mapping
+	missing []string
+	// exported source fields without a destination field
+	unused  []string
+	visited map[typePair]bool
+	// check tags and collect problems for Validate
+	validate bool
+	problems ConfigError
+	// struct pairs with checked tags, so problems are reported once
+	checkedTags map[typePair]bool
+}
+
+// CheckCoverage returns paths of destination fields that would not be populated
+// when mapping values of the type of src into values of the type of dst,
+// like Products[*].Link. Pointers are removed (first layer only).
+// Values are checked by their types, so nil pointers or empty slices are not taken into account.
+//
+// Returns an error if the types can't be mapped at all
+func (m *Mapper) CheckCoverage(dst, src interface{}) ([]string, error) {
+	diff, err := m.DiffFields(dst, src)
+	return diff.DestOnly, err
+}
+
+// FieldDiff lists struct fields that are not mapped between a source and a destination type
+type FieldDiff struct {
+	// SourceOnly are paths of exported source fields without a destination field,
+	// like Posts[*].AuthorID. Paths are destination paths of the containing structs.
+	SourceOnly []string
+	// DestOnly are paths of destination fields without a source, like with CheckCoverage
+	DestOnly []string
+}
+
+// DiffFields returns the struct fields of src and dst that are not mapped to each other
+// when mapping values of the type of src into values of the type of dst,
+// so CI checks can assert that new entity fields are either exposed or ignored with a tag.
+// Pointers are removed (first layer only).
+//
+// Returns an error if the types can't be mapped at all
+func (m *Mapper) DiffFields(dst, src interface{}) (FieldDiff, error) {
+	check := coverageCheck{
+		mapping: m.newMapping(),
+		visited: make(map[typePair]bool),
+	}
+	dstType := reflectValueRemovePtr(dst).Type()
+	srcType := reflectValueRemovePtr(src).Type()
+	if err := check.checkType(dstType, srcType); err != nil {
+		return FieldDiff{}, err
+	}
+	sort.Strings(check.missing)
+	sort.Strings(check.unused)
+	return FieldDiff{SourceOnly: check.unused, DestOnly: check.missing}, nil
+}
+
+// Check coverage of a type pair, mirroring mapValue
+func (cc *coverageCheck) checkType(dstType, srcType reflect.Type) error {
+	pair := typePair{dst: dstType, src: srcType}
+	if cc.visited[pair] {
+		return nil
+	}
+	cc.visited[pair] = true
+	defer delete(cc.visited, pair)
+
+	tk, fk := dstType.Kind(), srcType.Kind()
+
+	switch {
+	// 1. Conversion functions
+	case cc.hasConvFunc(dstType, srcType), isMappableFrom(dstType, srcType), isMappableInto(dstType, srcType):
+		return nil
+	case (isOpaqueKind(fk) || isOpaqueKind(tk)) && cc.opts.opaquePolicy == SkipOpaque:
+		return nil
+	case (isOpaqueKind(fk) || isOpaqueKind(tk)) && cc.opts.opaquePolicy == RejectOpaque:
+		cc.recordErrorPath()
+		return OpaqueValueError{ToType: dstType, FromType: srcType}
+	// 2-3. Assignment and conversion
+	case srcType.AssignableTo(dstType) || canConvert(dstType, srcType):
+		return nil
+	case cc.hasKindConverter(tk, fk), cc.opts.numberFormat != "" && tk == reflect.String && isNumericKind(fk):
+		return nil
+	// 4-5. Pointers
+	case fk == reflect.Ptr:
+		return cc.checkType(dstType, srcType.Elem())
+	case tk == reflect.Ptr:
+		return cc.checkType(dstType.Elem(), srcType)
+	// 4. Dynamic values of interfaces, sync.Map and results can't be checked
+	case fk == reflect.Interface || srcType == syncMapRfType || cc.resultOf(srcType) != nil:
+		return nil
+	case cc.hasOptional(dstType, srcType):
+		dstOpt, srcOpt := cc.optionalPair(dstType, srcType)
+		if srcOpt != nil {
+			return cc.checkType(dstType, srcOpt.elem)
+		}
+		return cc.checkType(dstOpt.elem, srcType)
+	case isAtomicType(srcType):
+		load, _ := reflect.PtrTo(srcType).MethodByName("Load")
+		return cc.checkType(dstType, load.Type.Out(0))
+	// 6. Ordered maps and custom collections
+	case tk == reflect.Map && isOrderedMap(srcType):
+		info := collectionInfoOf(srcType)
+		return cc.checkType(dstType, reflect.MapOf(info.orderedKey, info.orderedElem))
+	case tk == reflect.Slice && isOrderedMap(srcType):
+		return cc.checkType(dstType, reflect.SliceOf(collectionInfoOf(srcType).orderedElem))
+	case fk != reflect.Slice && isSourceCollection(srcType) && (tk == reflect.Slice || isDestCollection(dstType)):
+		return cc.checkType(dstType, reflect.SliceOf(collectionInfoOf(srcType).srcElem))
+	case fk == reflect.Slice && isDestCollection(dstType):
+		return cc.checkElem(collectionInfoOf(dstType).dstElem, srcType.Elem())
+	// 6. Structs
+	case tk == reflect.Struct && fk == reflect.Struct:
+		return cc.checkStructs(dstType, srcType)
+	// 7-8. Slices and maps
+	case tk == reflect.Slice && fk == reflect.Slice, tk == reflect.Map && fk == reflect.Map:
+		return cc.checkElem(dstType.Elem(), srcType.Elem())
+	case isStructToMap(dstType, srcType):
+		return cc.checkStructToMap(dstType, srcType)
+	// 9. Map (of slices) to slice
+	case tk == reflect.Slice && fk == reflect.Map:
+		err := cc.checkElem(dstType.Elem(), srcType.Elem())
+		if errors.As(err, &NoValidMappingError{}) && srcType.Elem().Kind() == reflect.Slice {
+			return cc.checkElem(dstType.Elem(), srcType.Elem().Elem())
+		}
+		return err
+	// 10-11. Unwrapping and wrapping
+	case fk == reflect.Slice && cc.opts.unwrapPolicy != NoUnwrap:
+		return cc.checkType(dstType, srcType.Elem())
+	case tk == reflect.Slice && cc.opts.wrapValues:
+		return cc.checkElem(dstType.Elem(), srcType)
+	}
+
+	cc.recordErrorPath()
+	return NoValidMappingError{ToType: dstType, FromType: srcType}
+}
+
+// Check if the source or destination type is optional
+func (cc *coverageCheck) hasOptional(dstType, srcType reflect.Type) bool {
+	dstOpt, srcOpt := cc.optionalPair(dstType, srcType)
+	return dstOpt != nil || srcOpt != nil
+}
+
+// Check if conversion functions apply to a type pair
+func (cc *coverageCheck) hasConvFunc(dstType, srcType reflect.Type) bool {
+	if cc.opts.strictTypes && isUniversalType(srcType) && isUniversalType(dstType) {
+		return false
+	}
+	_, ok := cc.findConvFunc(dstType, srcType)
+	return ok
+}
+
+// Check coverage of collection elements
+func (cc *coverageCheck) checkElem(dstType, srcType reflect.Type) error {
+	cc.pushKey(reflect.ValueOf("*"))
+	err := cc.checkType(dstType, srcType)
+	cc.popPath()
+	return err
+}
+
+// Check coverage of struct fields, mirroring mapStructs
+func (cc *coverageCheck) checkStructs(dstType, srcType reflect.Type) error {
+	owner := cc.owner
+	cc.owner = dstType
+	defer func() { cc.owner = owner }()
+
+	fromFields := make(map[string]structFieldInfo)
+	// outermost destination fields populated by source fields with the to tag
+	targets := make(map[string]bool)
+	for _, info := range structFieldInfos(srcType) {
+		fromFields[info.key] = info
+		if info.tags.to != "" && info.exported {
+			targets[strings.SplitN(info.tags.to, ".", 2)[0]] = true
+		}
+	}
+	checkTags := cc.validate && !cc.checkedTags[typePair{dst: dstType, src: srcType}]
+	if checkTags {
+		cc.checkedTags[typePair{dst: dstType, src: srcType}] = true
+	}
+
+	matched := make(map[string]bool)
+	// source fields without a destination field are collected by remain fields
+	remain := false
+	for _, toInfo := range structFieldInfos(dstType) {
+		remain = remain || toInfo.tags.remain
+		if (!toInfo.exported && !cc.opts.unexportedFields) || toInfo.tags.readonly {
+			continue
+		}
+		cc.pushField(toInfo.name)
+		fromInfo, ok := fromFields[toInfo.key]
+		ok = ok && fromInfo.tags.to == "" && !fromInfo.tags.ignoreOut && !toInfo.tags.overridesSource()
+		switch {
+		case toInfo.tags.from != "":
+			fromInfo, ok = fromFieldInfo(srcType, toInfo.tags.from)
+			// the outermost source fields count as mapped
+			for _, path := range strings.Split(toInfo.tags.from, "|") {
+				if outer, found := structFieldInfoPath(srcType, strings.SplitN(path, ".", 2)[0]); found {
+					matched[outer.key] = true
+				}
+			}
+		case !ok && !toInfo.exported:
+			fromInfo, ok = fromFields[exportedName(toInfo.key)]
+		case !ok && cc.opts.unexportedSources:
+			fromInfo, ok = fromFields[unexportedName(toInfo.key)]
+		}
+		if !ok && toInfo.tags.from == "" {
+			if key, matched := cc.matchSourceKey(srcType, toInfo.key, toInfo.rawTag); matched {
+				fromInfo = fromFields[key]
+				ok = fromInfo.tags.to == "" && !fromInfo.tags.ignoreOut && !toInfo.tags.overridesSource()
+			}
+		}
+		if ok && !fromInfo.exported {
+			ok = cc.opts.unexportedSources && isBasicKind(srcType.FieldByIndex(fromInfo.index).Type.Kind())
+		}
+		if checkTags {
+			var fromType reflect.Type
+			if ok {
+				fromType = srcType.FieldByIndex(fromInfo.index).Type
+			}
+			cc.checkTags(toInfo, dstType.FieldByIndex(toInfo.index).Type, fromType, srcType, fromFields)
+		}
+		var err error
+		if ok {
+			if toInfo.tags.from == "" {
+				matched[fromInfo.key] = true
+			}
+			err = cc.checkField(toInfo, fromInfo, dstType, srcType)
+		} else if _, selected := cc.findSourceSelector(dstType, srcType, toInfo.name); !selected &&
+			!toInfo.tags.derived() && !targets[toInfo.name] {
+			cc.missing = append(cc.missing, cc.pathString())
+			if checkTags && toInfo.tags.required {
+				cc.addProblem(FieldNotFoundError{Type: srcType, Field: toInfo.name})
+			}
+		}
+		cc.popPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, fromInfo := range structFieldInfos(srcType) {
+		if checkTags && fromInfo.tags.to != "" {
+			if _, ok := structFieldInfoPath(dstType, fromInfo.tags.to); !ok {
+				cc.addProblem(FieldNotFoundError{Type: dstType, Field: fromInfo.tags.to})
+			}
+		}
+		if fromInfo.exported && !matched[fromInfo.key] && !remain && fromInfo.tags.to == "" && !fromInfo.tags.ignoreOut {
+			cc.pushField(fromInfo.name)
+			cc.unused = append(cc.unused, cc.pathString())
+			cc.popPath()
+		}
+	}
+	return nil
+}
+
+// Check coverage of struct fields mapped to map entries
+func (cc *coverageCheck) checkStructToMap(dstType, srcType reflect.Type) error {
+	for _, info := range structFieldInfos(srcType) {
+		if !info.exported || info.tags.ignoreOut {
+			continue
+		}
+		cc.pushKey(reflect.ValueOf(info.mapKey()))
+		err := cc.checkType(dstType.Elem(), srcType.FieldByIndex(info.index).Type)
+		cc.popPath()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check coverage of a struct field, taking its tags into account
+func (cc *coverageCheck) checkField(toInfo, fromInfo structFieldInfo, dstType, srcType reflect.Type) error {
+	toType := dstType.FieldByIndex(toInfo.index).Type
+	fromType := srcType.FieldByIndex(fromInfo.index).Type
+	for fromType.Kind() == reflect.Ptr {
+		fromType = fromType.Elem()
+	}
+	tags := toInfo.tags
+
+	switch {
+	case tags.index != "" && toType.Kind() == reflect.Map && fromType.Kind() == reflect.Slice:
+		return cc.checkElem(toType.Elem(), fromType.Elem())
+	case tags.groupBy != "" && toType.Kind() == reflect.Map && toType.Elem().Kind() == reflect.Slice &&
+		fromType.Kind() == reflect.Slice:
+		return cc.checkElem(toType.Elem().Elem(), fromType.Elem())
+	case tags.unwrap != NoUnwrap && fromType.Kind() == reflect.Slice && toType.Kind() != reflect.Slice:
+		return cc.checkType(toType, fromType.Elem())
+	case tags.wrap && toType.Kind() == reflect.Slice && fromType.Kind() != reflect.Slice:
+		return cc.checkElem(toType.Elem(), fromType)
+	case tags.json && isJSONPair(toType, fromType):
+		return nil
+	case tags.flags != "" || tags.flag != "":
+		return nil
+	case tags.format != "" && derefType(toType).Kind() == reflect.String && isNumericKind(fromType.Kind()):
+		return nil
+	}
+	return cc.checkType(toType, fromType)
+}
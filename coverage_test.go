@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Report destination fields without source fields
+func TestCheckCoverage(t *testing.T) {
+	type ProductDto struct {
+		Name string
+		Link string
+	}
+	type CartDto struct {
+		Products []*ProductDto
+		Tagged   map[string]ProductDto
+		Owner    string
+	}
+	type Cart struct {
+		Products []Product
+		Tagged   map[string]ProductRef
+	}
+
+	m := Mapper{}
+	missing, err := m.CheckCoverage(CartDto{}, &Cart{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Owner", "Products[*].Link"}, missing)
+
+	// fully covered
+	missing, err = m.CheckCoverage(&[]Product{}, []ProductRef{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
+
+// Fields of both types without a counterpart are reported
+func TestDiffFields(t *testing.T) {
+	type PostDto struct {
+		Title string
+		Likes int
+	}
+	type UserDto struct {
+		Name  string
+		Posts []PostDto
+	}
+	type Post struct {
+		Title    string
+		AuthorID int
+		Draft    bool `dto:"ignore"`
+	}
+	type User struct {
+		Name         string
+		PasswordHash string
+		Posts        []Post
+		session      string
+	}
+
+	m := Mapper{}
+	diff, err := m.DiffFields(UserDto{}, User{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"PasswordHash", "Posts[*].AuthorID"}, diff.SourceOnly)
+	assert.Equal(t, []string{"Posts[*].Likes"}, diff.DestOnly)
+}
+
+// Fail on types that can't be mapped
+func TestCheckCoverageInvalid(t *testing.T) {
+	var out struct {
+		Name int
+	}
+	m := Mapper{}
+	_, err := m.CheckCoverage(out, Product{})
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+
+	m.AddConvFunc(func(s string) int { return len(s) })
+	missing, err := m.CheckCoverage(out, Product{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
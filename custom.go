@@ -0,0 +1,176 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+)
+
+var intRfType = reflect.TypeOf(0)
+
+// Element types of a custom collection, nil if it can't be used as source or destination
+type collectionInfo struct {
+	srcElem reflect.Type
+	dstElem reflect.Type
+	// key and value types of an ordered map, nil if it is none
+	orderedKey  reflect.Type
+	orderedElem reflect.Type
+}
+
+// Cache of custom collection element types by type
+var collectionCache sync.Map
+
+// Get the custom collection info of a type, cached
+func collectionInfoOf(rfType reflect.Type) collectionInfo {
+	if info, ok := collectionCache.Load(rfType); ok {
+		return info.(collectionInfo)
+	}
+	var info collectionInfo
+	info.srcElem, _ = sourceCollectionElem(rfType)
+	info.dstElem, _ = destCollectionElem(rfType)
+	info.orderedKey, info.orderedElem, _ = orderedMapTypes(rfType)
+	collectionCache.Store(rfType, info)
+	return info
+}
+
+// Find a method of a value or its pointer, copying the value if it is not addressable
+func findMethod(rv reflect.Value, name string) (reflect.Value, bool) {
+	if method := rv.MethodByName(name); method.IsValid() {
+		return method, true
+	}
+	if _, ok := reflect.PtrTo(rv.Type()).MethodByName(name); !ok {
+		return reflect.Value{}, false
+	}
+	if !rv.CanAddr() {
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		rv = ptr.Elem()
+	}
+	return rv.Addr().MethodByName(name), true
+}
+
+// Check if a type is a custom source collection with Len() int and Index(int) T methods
+func isSourceCollection(rfType reflect.Type) bool {
+	return collectionInfoOf(rfType).srcElem != nil
+}
+
+// Get the element type of a custom source collection
+func sourceCollectionElem(rfType reflect.Type) (reflect.Type, bool) {
+	if rfType.Kind() == reflect.Ptr || rfType.Kind() == reflect.Interface {
+		return nil, false
+	}
+	ptrType := reflect.PtrTo(rfType)
+	lenMethod, ok := ptrType.MethodByName("Len")
+	if !ok || lenMethod.Type.NumIn() != 1 || lenMethod.Type.NumOut() != 1 || lenMethod.Type.Out(0) != intRfType {
+		return nil, false
+	}
+	indexMethod, ok := ptrType.MethodByName("Index")
+	if !ok || indexMethod.Type.NumIn() != 2 || indexMethod.Type.In(1) != intRfType || indexMethod.Type.NumOut() != 1 {
+		return nil, false
+	}
+	return indexMethod.Type.Out(0), true
+}
+
+// Check if a type is a custom destination collection with an Append(T) method
+func isDestCollection(rfType reflect.Type) bool {
+	return collectionInfoOf(rfType).dstElem != nil
+}
+
+// Get the element type of a custom destination collection with an Append(T) method
+func destCollectionElem(rfType reflect.Type) (reflect.Type, bool) {
+	if rfType.Kind() == reflect.Ptr || rfType.Kind() == reflect.Interface {
+		return nil, false
+	}
+	appendMethod, ok := reflect.PtrTo(rfType).MethodByName("Append")
+	if !ok || appendMethod.Type.NumIn() != 2 {
+		return nil, false
+	}
+	return appendMethod.Type.In(1), true
+}
+
+// Copy the elements of a custom source collection into a slice
+func collectionToSlice(srcRv reflect.Value) reflect.Value {
+	elemType := collectionInfoOf(srcRv.Type()).srcElem
+	lenMethod, _ := findMethod(srcRv, "Len")
+	indexMethod, _ := findMethod(srcRv, "Index")
+
+	n := int(lenMethod.Call(nil)[0].Int())
+	out := reflect.MakeSlice(reflect.SliceOf(elemType), n, n)
+	for i := 0; i < n; i++ {
+		out.Index(i).Set(indexMethod.Call([]reflect.Value{reflect.ValueOf(i)})[0])
+	}
+	return out
+}
+
+// Map slice elements into a custom destination collection by appending them.
+// The collection is reset first, so its elements are replaced like the ones of slices.
+func (m *mapping) mapSliceToCollection(dstRv, srcRv reflect.Value) error {
+	elemType := collectionInfoOf(dstRv.Type()).dstElem
+	dstRv.Set(reflect.Zero(dstRv.Type()))
+	appendMethod := dstRv.Addr().MethodByName("Append")
+	for i := 0; i < srcRv.Len(); i++ {
+		elem := reflect.New(elemType).Elem()
+		m.pushIndex(i)
+		err := m.mapValue(elem, srcRv.Index(i))
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		appendMethod.Call([]reflect.Value{elem})
+	}
+	return nil
+}
+
+// Check if a type is an ordered map with Keys() []K and Get(K) (V, bool) methods,
+// like the ordered maps of third-party libraries
+func isOrderedMap(rfType reflect.Type) bool {
+	return collectionInfoOf(rfType).orderedKey != nil
+}
+
+// Get the key and value types of an ordered map
+func orderedMapTypes(rfType reflect.Type) (reflect.Type, reflect.Type, bool) {
+	if rfType.Kind() == reflect.Ptr || rfType.Kind() == reflect.Interface {
+		return nil, nil, false
+	}
+	ptrType := reflect.PtrTo(rfType)
+	keysMethod, ok := ptrType.MethodByName("Keys")
+	if !ok || keysMethod.Type.NumIn() != 1 || keysMethod.Type.NumOut() != 1 || keysMethod.Type.Out(0).Kind() != reflect.Slice {
+		return nil, nil, false
+	}
+	keyType := keysMethod.Type.Out(0).Elem()
+	getMethod, ok := ptrType.MethodByName("Get")
+	if !ok || getMethod.Type.NumIn() != 2 || getMethod.Type.In(1) != keyType ||
+		getMethod.Type.NumOut() != 2 || getMethod.Type.Out(1).Kind() != reflect.Bool {
+		return nil, nil, false
+	}
+	return keyType, getMethod.Type.Out(0), true
+}
+
+// Get the keys of an ordered map in order
+func orderedMapKeys(srcRv reflect.Value) reflect.Value {
+	keysMethod, _ := findMethod(srcRv, "Keys")
+	return keysMethod.Call(nil)[0]
+}
+
+// Copy the entries of an ordered map into a map, or its values in order into a slice
+func orderedMapEntries(srcRv reflect.Value, toSlice bool) reflect.Value {
+	info := collectionInfoOf(srcRv.Type())
+	getMethod, _ := findMethod(srcRv, "Get")
+	keys := orderedMapKeys(srcRv)
+
+	out := reflect.MakeMapWithSize(reflect.MapOf(info.orderedKey, info.orderedElem), keys.Len())
+	if toSlice {
+		out = reflect.MakeSlice(reflect.SliceOf(info.orderedElem), 0, keys.Len())
+	}
+	for i := 0; i < keys.Len(); i++ {
+		entry := getMethod.Call([]reflect.Value{keys.Index(i)})
+		switch {
+		case !entry[1].Bool():
+			continue
+		case toSlice:
+			out = reflect.Append(out, entry[0])
+		default:
+			out.SetMapIndex(keys.Index(i), entry[0])
+		}
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type productList struct {
+	items []Product
+}
+
+func (pl productList) Len() int {
+	return len(pl.items)
+}
+
+func (pl productList) Index(i int) Product {
+	return pl.items[i]
+}
+
+func (pl *productList) Append(p Product) {
+	pl.items = append(pl.items, p)
+}
+
+type productRefList struct {
+	refs []*ProductRef
+}
+
+func (prl *productRefList) Append(ref *ProductRef) {
+	prl.refs = append(prl.refs, ref)
+}
+
+// Custom collections are mapped from and into like slices
+func TestCustomCollections(t *testing.T) {
+	from := productList{items: commonProducts[:2]}
+
+	var outSlice []ProductRef
+	err := Map(&outSlice, from)
+	assert.Nil(t, err)
+	assert.Equal(t, []ProductRef{{Product: commonProducts[0]}, {Product: commonProducts[1]}}, outSlice)
+
+	var outList productRefList
+	err = Map(&outList, &from)
+	assert.Nil(t, err)
+	assert.Len(t, outList.refs, 2)
+	assert.Equal(t, commonProducts[1], outList.refs[1].Product)
+
+	var outCart struct{ Products productList }
+	err = Map(&outCart, ShoppingCart{Products: commonProducts[:3]})
+	assert.Nil(t, err)
+	assert.Equal(t, commonProducts[:3], outCart.Products.items)
+}
+
+// Elements of custom collections are replaced when mapping into them again
+func TestCustomCollectionRemap(t *testing.T) {
+	var out struct{ Products productList }
+	for i := 0; i < 2; i++ {
+		err := Map(&out, ShoppingCart{Products: commonProducts[:2]})
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, commonProducts[:2], out.Products.items)
+
+	err := Map(&out, ShoppingCart{Products: commonProducts[2:3]}, WithUpdatePolicy(ReuseExisting))
+	assert.Nil(t, err)
+	assert.Equal(t, commonProducts[2:3], out.Products.items)
+}
+
+// Custom collections are covered by coverage checks
+func TestCustomCollectionCoverage(t *testing.T) {
+	m := Mapper{}
+	missing, err := m.CheckCoverage(&productRefList{}, productList{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"[*].Link"}, missing)
+}
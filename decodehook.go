@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"reflect"
+)
+
+// Closure of a decode hook, returns the new source value
+type decodeHookClosure = func(dstRv, srcRv reflect.Value) (interface{}, error)
+
+var (
+	typeHookRfType  = reflect.TypeOf(func(reflect.Type, reflect.Type, interface{}) (interface{}, error) { return nil, nil })
+	kindHookRfType  = reflect.TypeOf(func(reflect.Kind, reflect.Kind, interface{}) (interface{}, error) { return nil, nil })
+	valueHookRfType = reflect.TypeOf(func(reflect.Value, reflect.Value) (interface{}, error) { return nil, nil })
+)
+
+// AddDecodeHook adds a decode hook in the style of mapstructure, to ease migrating
+// from it without rewriting hooks. Hooks of the types DecodeHookFuncType,
+// DecodeHookFuncKind and DecodeHookFuncValue are supported, without depending on mapstructure.
+//
+// Hooks run for every value that no conversion function applies to, in order of registration,
+// each receiving the result of the previous one. Mapping continues with the returned value,
+// so hooks have to return their input unchanged if they don't apply. Nil results are handled
+// like nil pointers by the nil policy. Values can't be checked by CheckCoverage.
+//
+// Panics if hook is not a valid decode hook
+func (m *Mapper) AddDecodeHook(hook interface{}) {
+	hookRv := reflect.ValueOf(hook)
+	var closure decodeHookClosure
+	switch {
+	case hookRv.Type().ConvertibleTo(typeHookRfType):
+		fn := hookRv.Convert(typeHookRfType).Interface().(func(reflect.Type, reflect.Type, interface{}) (interface{}, error))
+		closure = func(dstRv, srcRv reflect.Value) (interface{}, error) {
+			return fn(srcRv.Type(), dstRv.Type(), srcRv.Interface())
+		}
+	case hookRv.Type().ConvertibleTo(kindHookRfType):
+		fn := hookRv.Convert(kindHookRfType).Interface().(func(reflect.Kind, reflect.Kind, interface{}) (interface{}, error))
+		closure = func(dstRv, srcRv reflect.Value) (interface{}, error) {
+			return fn(srcRv.Kind(), dstRv.Kind(), srcRv.Interface())
+		}
+	case hookRv.Type().ConvertibleTo(valueHookRfType):
+		fn := hookRv.Convert(valueHookRfType).Interface().(func(reflect.Value, reflect.Value) (interface{}, error))
+		closure = func(dstRv, srcRv reflect.Value) (interface{}, error) {
+			return fn(srcRv, dstRv)
+		}
+	default:
+		panic("Bad decode hook")
+	}
+
+	m.updateRegistry(func(r *registry) {
+		r.decodeHooks = append(r.decodeHooks, closure)
+	})
+}
+
+// Run decode hooks on a source value
+// Returns false if a hook returned nil
+func (m *mapping) runDecodeHooks(dstRv, srcRv reflect.Value) (reflect.Value, bool, error) {
+	if len(m.decodeHooks) == 0 || !srcRv.CanInterface() || m.opts.skips(DecodeStage) {
+		return srcRv, true, nil
+	}
+	for _, hook := range m.decodeHooks {
+		out, err := hook(dstRv, srcRv)
+		if err != nil {
+			return srcRv, false, ConversionError{Path: m.pathString(), ToType: dstRv.Type(), FromType: srcRv.Type(), Err: err}
+		}
+		if out == nil {
+			return srcRv, false, m.mapNil(dstRv, srcRv)
+		}
+		srcRv = reflect.ValueOf(out)
+	}
+	return srcRv, true, nil
+}
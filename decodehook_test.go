@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Same underlying types as the mapstructure hook types
+type testDecodeHookFuncType func(reflect.Type, reflect.Type, interface{}) (interface{}, error)
+type testDecodeHookFuncKind func(reflect.Kind, reflect.Kind, interface{}) (interface{}, error)
+
+// Decode hooks transform source values
+func TestDecodeHooks(t *testing.T) {
+	type ConfigDto struct {
+		Tags    []string
+		Port    int
+		Timeout string
+	}
+	type Config struct {
+		Tags    string
+		Port    string
+		Timeout int
+	}
+
+	mapper := Mapper{}
+	// like mapstructure.StringToSliceHookFunc
+	mapper.AddDecodeHook(testDecodeHookFuncKind(func(from, to reflect.Kind, data interface{}) (interface{}, error) {
+		if from != reflect.String || to != reflect.Slice {
+			return data, nil
+		}
+		return strings.Split(data.(string), ","), nil
+	}))
+	mapper.AddDecodeHook(testDecodeHookFuncType(func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to.Kind() != reflect.Int {
+			return data, nil
+		}
+		return strconv.Atoi(data.(string))
+	}))
+	mapper.AddDecodeHook(func(from, to reflect.Value) (interface{}, error) {
+		if from.Kind() != reflect.Int || to.Kind() != reflect.String {
+			return from.Interface(), nil
+		}
+		return strconv.Itoa(int(from.Int())) + "s", nil
+	})
+
+	var out ConfigDto
+	err := mapper.Map(&out, Config{Tags: "a,b", Port: "8080", Timeout: 30})
+	assert.Nil(t, err)
+	assert.Equal(t, ConfigDto{Tags: []string{"a", "b"}, Port: 8080, Timeout: "30s"}, out)
+
+	// errors are wrapped
+	err = mapper.Map(&out, Config{Port: "http"})
+	var convErr ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "Port", convErr.Path)
+
+	assert.Panics(t, func() {
+		mapper.AddDecodeHook(func(data interface{}) interface{} { return data })
+	})
+}
+
+// Nil results are handled by the nil policy
+func TestDecodeHookNil(t *testing.T) {
+	mapper := Mapper{}
+	mapper.AddDecodeHook(func(from, to reflect.Kind, data interface{}) (interface{}, error) {
+		if s, ok := data.(string); ok && s == "" {
+			return nil, nil
+		}
+		return data, nil
+	})
+
+	type UserDto struct {
+		Name string
+	}
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	out := UserDto{"untouched"}
+	err := mapper.Map(&out, User{})
+	assert.Nil(t, err)
+	assert.Equal(t, "untouched", out.Name)
+
+	err = mapper.Map(&out, User{}, WithNilPolicy(RejectNil))
+	assert.True(t, errors.As(err, &NilValueError{}))
+}
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// UnaddressableError indicates that the destination of a Map call can't be set,
+// because it was passed by value. Values of map entries and interfaces can't be
+// mapped into directly, as they are copies.
+type UnaddressableError struct {
+	// Type is nil if the destination is a nil pointer
+	Type reflect.Type
+}
+
+func (ue UnaddressableError) Error() string {
+	if ue.Type == nil {
+		return "Destination is a nil pointer, pass the address of a value like &dst"
+	}
+	return fmt.Sprintf("Destination of type %v can't be set, pass its address like &dst. "+
+		"Map entries and values in interfaces are copies, so map into a variable and store it back",
+		typeName(ue.Type))
+}
+
+// Check if the destination of a Map call can be set
+func checkDestination(dstRv reflect.Value) error {
+	switch {
+	case !dstRv.IsValid():
+		return UnaddressableError{}
+	case !dstRv.CanSet():
+		return UnaddressableError{Type: dstRv.Type()}
+	}
+	return nil
+}
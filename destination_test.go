@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Destinations passed by value fail with an UnaddressableError
+func TestUnaddressableDestination(t *testing.T) {
+	var out struct{ Name string }
+	err := Map(out, commonProducts[0])
+	assert.ErrorAs(t, err, &UnaddressableError{})
+
+	entries := map[string]struct{ Name string }{"shirt": {}}
+	err = Map(entries["shirt"], commonProducts[0])
+	assert.ErrorAs(t, err, &UnaddressableError{})
+
+	var boxed interface{} = out
+	err = MapInto(boxed, "Name", "Shirt")
+	assert.ErrorAs(t, err, &UnaddressableError{})
+
+	var nilPtr *struct{ Name string }
+	err = Map(nilPtr, commonProducts[0])
+	assert.EqualError(t, err, "Destination is a nil pointer, pass the address of a value like &dst")
+}
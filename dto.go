@@ -18,10 +18,10 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
-type structValueMap = map[string]reflect.Value
-
 // Marker type for functions with no receiver
 type nilRecvT struct{}
 
@@ -29,8 +29,10 @@ var nilRecvRfType = reflect.TypeOf(nilRecvT{})
 var errorRfType = reflect.TypeOf((*error)(nil)).Elem()
 var mapperPtrRfType = reflect.TypeOf((*Mapper)(nil))
 
-type convertFuncClosure = func(reflect.Value, *Mapper) (reflect.Value, error)
-type inspectFuncClosure = func(reflect.Value, reflect.Value, *Mapper) error
+type convertFuncClosure = func(reflect.Value, *mapping) (reflect.Value, error)
+type inspectFuncClosure = func(reflect.Value, reflect.Value, *mapping) error
+type lessFuncClosure = func(reflect.Value, reflect.Value) bool
+type filterFuncClosure = func(reflect.Value) (bool, error)
 
 const structTag = "dto"
 
@@ -41,36 +43,134 @@ type NoValidMappingError struct {
 }
 
 func (nvme NoValidMappingError) Error() string {
-	return fmt.Sprintf("No valid mapping found for %v from %v", nvme.ToType, nvme.FromType)
+	return fmt.Sprintf("No valid mapping found for %v from %v", typeName(nvme.ToType), typeName(nvme.FromType))
 }
 
-// Mapper contains conversion and inspect functions
-type Mapper struct {
-	// linear search might be faster than nested maps
-	convFunc map[reflect.Type]map[reflect.Type]convertFuncClosure
-	postFunc map[reflect.Type]map[reflect.Type][]inspectFuncClosure
+// NilValueError indicates that a nil source was rejected by the nil policy
+type NilValueError struct {
+	ToType   reflect.Type
+	FromType reflect.Type
 }
 
-// ==================================== utils =================================
+func (nve NilValueError) Error() string {
+	return fmt.Sprintf("Nil value of %v can't be mapped to %v", typeName(nve.FromType), typeName(nve.ToType))
+}
 
-// Collect all struct fields (including anonymous) into a structValueMap
-func collectStructFields(rfValue reflect.Value, rfType reflect.Type, fields structValueMap) {
-	for i := 0; i < rfType.NumField(); i++ {
-		fieldValue := rfValue.Field(i)
-		fieldType := rfType.Field(i)
-		if tags, ok := fieldType.Tag.Lookup(structTag); ok {
-			if strings.Contains(tags, "ignore") {
-				continue
-			}
-		}
-		if fieldType.Anonymous {
-			collectStructFields(fieldValue, fieldType.Type, fields)
-		} else {
-			fields[fieldType.Name] = fieldValue
-		}
+// AbsentValueError indicates that an absent optional source was rejected by the absent policy
+type AbsentValueError struct {
+	ToType   reflect.Type
+	FromType reflect.Type
+}
+
+func (ave AbsentValueError) Error() string {
+	return fmt.Sprintf("Absent value of %v can't be mapped to %v", typeName(ave.FromType), typeName(ave.ToType))
+}
+
+// OpaqueValueError indicates that a function, channel or unsafe pointer
+// was rejected by the opaque policy
+type OpaqueValueError struct {
+	ToType   reflect.Type
+	FromType reflect.Type
+}
+
+func (ove OpaqueValueError) Error() string {
+	return fmt.Sprintf("Opaque value of %v can't be mapped to %v", typeName(ove.FromType), typeName(ove.ToType))
+}
+
+// FieldNotFoundError indicates that a field referenced by a tag doesn't exist
+type FieldNotFoundError struct {
+	Type  reflect.Type
+	Field string
+}
+
+func (fnfe FieldNotFoundError) Error() string {
+	return fmt.Sprintf("Field %v not found in %v", fnfe.Field, typeName(fnfe.Type))
+}
+
+// TagError indicates that a tag can't be applied to a field
+type TagError struct {
+	Tag    string
+	Type   reflect.Type
+	Reason string
+}
+
+func (te TagError) Error() string {
+	return fmt.Sprintf("Tag %v can't be applied to %v: %v", te.Tag, typeName(te.Type), te.Reason)
+}
+
+// AmbiguousSliceError indicates that a slice with multiple elements
+// can't be mapped to a single value
+type AmbiguousSliceError struct {
+	ToType   reflect.Type
+	FromType reflect.Type
+	Len      int
+}
+
+func (ase AmbiguousSliceError) Error() string {
+	return fmt.Sprintf("Slice %v with %v elements can't be mapped to a single %v",
+		typeName(ase.FromType), ase.Len, typeName(ase.ToType))
+}
+
+// ParseError indicates that a string value couldn't be parsed
+type ParseError struct {
+	Value string
+	Type  reflect.Type
+}
+
+func (pe ParseError) Error() string {
+	return fmt.Sprintf("Failed to parse %q as %v", pe.Value, typeName(pe.Type))
+}
+
+// ConversionError wraps an error returned by a conversion function
+// with the destination path and the types of the conversion
+type ConversionError struct {
+	Path     string
+	ToType   reflect.Type
+	FromType reflect.Type
+	Err      error
+}
+
+func (ce ConversionError) Error() string {
+	if ce.Path == "" {
+		return fmt.Sprintf("Failed to convert %v to %v: %v", typeName(ce.FromType), typeName(ce.ToType), ce.Err)
+	}
+	return fmt.Sprintf("Failed to convert %v to %v at %v: %v", typeName(ce.FromType), typeName(ce.ToType), ce.Path, ce.Err)
+}
+
+func (ce ConversionError) Unwrap() error {
+	return ce.Err
+}
+
+// MapKeyError indicates that a map key couldn't be mapped
+type MapKeyError struct {
+	ToType   reflect.Type
+	FromType reflect.Type
+	// Err is nil if the mapped key is not hashable
+	Err error
+}
+
+func (mke MapKeyError) Error() string {
+	if mke.Err == nil {
+		return fmt.Sprintf("Key of %v mapped to %v is not hashable", typeName(mke.FromType), typeName(mke.ToType))
 	}
+	return fmt.Sprintf("Failed to map key %v to %v: %v", typeName(mke.FromType), typeName(mke.ToType), mke.Err)
 }
 
+func (mke MapKeyError) Unwrap() error {
+	return mke.Err
+}
+
+// Mapper contains conversion and inspect functions
+//
+// It is safe to register functions and change options while mapping
+type Mapper struct {
+	// *mapperState, replaced as a whole on every change
+	state atomic.Value
+	mu    sync.Mutex
+}
+
+// ==================================== utils =================================
+
 // Return reflect.Value with pointer removed (first layer only)
 func reflectValueRemovePtr(v interface{}) reflect.Value {
 	rv := reflect.ValueOf(v)
@@ -80,6 +180,65 @@ func reflectValueRemovePtr(v interface{}) reflect.Value {
 	return rv
 }
 
+// Check if a kind is a basic kind, i.e. a boolean, numeric or string kind
+func isBasicKind(kind reflect.Kind) bool {
+	return kind >= reflect.Bool && kind <= reflect.Complex128 || kind == reflect.String
+}
+
+// Check if a value can be used as a map key, including dynamic types of interfaces
+func isHashable(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Interface:
+		return rv.IsNil() || isHashable(rv.Elem())
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if !isHashable(rv.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if !isHashable(rv.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return rv.Type().Comparable()
+}
+
+// Check if a value is a nil pointer at any level
+func isNilPointer(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+	return false
+}
+
+// Check if a value is empty, i.e. a zero scalar or an empty slice or map
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return rv.IsZero()
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return false
+}
+
+// Check if a type is predeclared or unnamed, i.e. not defined by a package
+// Type aliases can't be distinguished from the types they denote
+func isUniversalType(rt reflect.Type) bool {
+	return rt.PkgPath() == ""
+}
+
 // Maps an error from a reflect value
 // Panics if the value is non nill and not an error
 func errorFromReflectValue(rv reflect.Value) error {
@@ -96,12 +255,14 @@ func errorFromReflectValue(rv reflect.Value) error {
 // ==================================== Conversion and inspection functions ===
 
 // Run inspect functions for (dst-src) pair
-func (m *Mapper) runInspectFuncs(dstRv, srcRv reflect.Value) error {
-	toMap, ok := m.postFunc[dstRv.Type()]
-	if !ok {
-		return nil
+// Functions without a source are skipped if they already ran for an unwrapped source.
+func (m *mapping) runInspectFuncs(dstRv, srcRv reflect.Value, unwrapped bool) error {
+	toMap := m.postFunc[dstRv.Type()]
+	recvTypes := []reflect.Type{srcRv.Type(), nilRecvRfType}
+	if unwrapped {
+		recvTypes = recvTypes[:1]
 	}
-	for _, recvType := range []reflect.Type{srcRv.Type(), nilRecvRfType} {
+	for _, recvType := range recvTypes {
 		funcs, ok := toMap[recvType]
 		if !ok {
 			continue
@@ -112,37 +273,81 @@ func (m *Mapper) runInspectFuncs(dstRv, srcRv reflect.Value) error {
 			}
 		}
 	}
-	return nil
+	return m.runPathInspectFuncs(dstRv, srcRv, unwrapped)
 }
 
+// Find convert function for (dst-src) pair
+func (m *mapping) findConvFunc(dstType, srcType reflect.Type) (convertFuncClosure, bool) {
+	if convertFunc, ok := m.opts.findCallConvFunc(dstType, srcType); ok {
+		return convertFunc, true
+	}
+	if m.owner != nil {
+		if convertFunc, ok := m.ownerFunc[m.owner][srcType][dstType]; ok {
+			return convertFunc, true
+		}
+	}
+	if m.opts.scope != "" {
+		if convertFunc, ok := m.scopeFunc[m.opts.scope][srcType][dstType]; ok {
+			return convertFunc, true
+		}
+	}
+	if convertFunc, ok := m.convFunc[srcType][dstType]; ok {
+		return convertFunc, true
+	}
+	return m.findFallbackConvFunc(dstType, srcType)
+}
+
+// Find a conversion function for families of types, created by factories or registered for underlying types
+func (m *mapping) findFallbackConvFunc(dstType, srcType reflect.Type) (convertFuncClosure, bool) {
+	if convertFunc, ok := m.findFactoryFunc(dstType, srcType); ok {
+		return convertFunc, true
+	}
+	if convertFunc, ok := m.baseFunc[srcType.Kind()][dstType]; ok {
+		return convertFunc, true
+	}
+	return nil, false
+}
+
+// Returned by built-in conversion functions that don't apply to a value,
+// which is then mapped as if they didn't exist. Only functions for exact types may return it.
+var errNotConverted = errors.New("not converted")
+
 // Run convert function for (dst-src) pair
 // Returns (error, true) if a valid function was found, (nil, false) otherwise
-func (m *Mapper) runConvFuncs(dstRv, srcRv reflect.Value) (bool, error) {
-	toMap, ok := m.convFunc[srcRv.Type()]
+func (m *mapping) runConvFuncs(dstRv, srcRv reflect.Value) (bool, error) {
+	if m.opts.skips(ConvertStage) || m.opts.strictTypes && isUniversalType(srcRv.Type()) && isUniversalType(dstRv.Type()) {
+		return false, nil
+	}
+	convertFunc, ok := m.findConvFunc(dstRv.Type(), srcRv.Type())
 	if !ok {
 		return false, nil
 	}
-	if convertFunc, ok := toMap[dstRv.Type()]; ok {
-		val, err := convertFunc(srcRv, m)
-		if err != nil {
-			return true, err
+	val, err := convertFunc(srcRv, m)
+	if err == errNotConverted {
+		if convertFunc, ok = m.findFallbackConvFunc(dstRv.Type(), srcRv.Type()); !ok {
+			return false, nil
 		}
-		dstRv.Set(val)
-		return true, nil
+		val, err = convertFunc(srcRv, m)
 	}
-	return false, nil
+	m.reportShadowed(dstRv.Type(), srcRv.Type())
+	if err != nil {
+		return true, ConversionError{Path: m.pathString(), ToType: dstRv.Type(), FromType: srcRv.Type(), Err: err}
+	}
+	dstRv.Set(val)
+	m.auditConversion(dstRv, srcRv)
+	return true, nil
 }
 
 // HasCustomFuncs returns true if the Mapper has custom functions defined
 func (m *Mapper) HasCustomFuncs() bool {
-	return len(m.convFunc)+len(m.postFunc) > 0
+	r := m.loadRegistry()
+	return len(r.convFunc)+len(r.baseFunc)+len(r.scopeFunc)+len(r.ownerFunc)+len(r.postFunc)+len(r.pathFunc)+len(r.finalizers)+
+		len(r.decodeHooks)+len(r.convFactories)+len(r.keyFuncs)+len(r.selectors)+len(r.kindConverters) > 0 || r.nameMatcher != nil
 }
 
-// AddConvFunc adds a conversion function to the Mapper
-//
-// Panics if f is not a valid conversion function
-// Overwrites previous functions with the same type pair
-func (m *Mapper) AddConvFunc(f interface{}) {
+// Make a closure for a conversion function
+// Returns its argument and result types
+func makeConvFuncClosure(f interface{}) (reflect.Type, reflect.Type, convertFuncClosure) {
 	rt := reflect.TypeOf(f)
 
 	// check basic argument invariant
@@ -150,10 +355,13 @@ func (m *Mapper) AddConvFunc(f interface{}) {
 		panic("Bad conversion function")
 	}
 
-	// check if to inject mapper
-	takesMapper := false
-	if rt.NumIn() > 1 && rt.In(1) == mapperPtrRfType {
-		takesMapper = true
+	// check if to inject mapper and field info
+	injected := make([]reflect.Type, 0, rt.NumIn()-1)
+	for i := 1; i < rt.NumIn(); i++ {
+		if rt.In(i) != mapperPtrRfType && rt.In(i) != fieldInfoRfType {
+			panic("Bad conversion function")
+		}
+		injected = append(injected, rt.In(i))
 	}
 
 	// check if returns an error
@@ -163,47 +371,167 @@ func (m *Mapper) AddConvFunc(f interface{}) {
 		returnsError = true
 	}
 
-	inType := rt.In(0)
-
-	// create maps
-	if len(m.convFunc) == 0 {
-		m.convFunc = make(map[reflect.Type]map[reflect.Type]convertFuncClosure)
-	}
-	if len(m.convFunc[inType]) == 0 {
-		m.convFunc[inType] = make(map[reflect.Type]convertFuncClosure)
-	}
-
-	// register closure
-	m.convFunc[inType][outType] = func(from reflect.Value, m *Mapper) (reflect.Value, error) {
-		args := []reflect.Value{from}
-		if takesMapper {
-			args = append(args, reflect.ValueOf(m))
-		}
+	closure := func(from reflect.Value, m *mapping) (reflect.Value, error) {
+		args := append([]reflect.Value{from}, m.injectArgs(injected)...)
 		out := reflect.ValueOf(f).Call(args)
 		if returnsError {
 			return out[0], errorFromReflectValue(out[1])
 		}
 		return out[0], nil
 	}
+	return rt.In(0), outType, closure
 }
 
-// AddInspectFunc adds an inspection function to the Mapper
+// AddConvFunc adds a conversion function to the Mapper
 //
-// Panics if f is not a valid inspection function
-func (m *Mapper) AddInspectFunc(f interface{}) {
+// Panics if f is not a valid conversion function
+// Overwrites previous functions with the same type pair
+func (m *Mapper) AddConvFunc(f interface{}, opts ...ConvFuncOption) {
+	inType, outType, closure := makeConvFuncClosure(f)
+	m.addConvFuncClosure(convFuncInfo(inType, outType, funcName(f), opts), applyConvFuncOptions(f, closure, opts))
+}
+
+// Register a conversion closure for a type pair
+func (m *Mapper) addConvFuncClosure(info ConverterInfo, closure convertFuncClosure) {
+	inType, outType := info.From, info.To
+	m.updateRegistry(func(r *registry) {
+		r.addConverterInfo(info)
+
+		// create maps
+		if len(r.convFunc) == 0 {
+			r.convFunc = make(map[reflect.Type]map[reflect.Type]convertFuncClosure)
+		}
+		if len(r.convFunc[inType]) == 0 {
+			r.convFunc[inType] = make(map[reflect.Type]convertFuncClosure)
+		}
+
+		// register closure
+		r.convFunc[inType][outType] = closure
+	})
+}
+
+// AddScopedConvFunc adds a conversion function that is applied only
+// when mapping with the scope selected by WithScope
+//
+// Panics if f is not a valid conversion function
+// Overwrites previous functions with the same scope and type pair
+func (m *Mapper) AddScopedConvFunc(scope string, f interface{}, opts ...ConvFuncOption) {
+	inType, outType, closure := makeConvFuncClosure(f)
+	closure = applyConvFuncOptions(f, closure, opts)
+
+	m.updateRegistry(func(r *registry) {
+		info := convFuncInfo(inType, outType, funcName(f), opts)
+		info.Scope = scope
+		r.addConverterInfo(info)
+
+		// create maps
+		if len(r.scopeFunc) == 0 {
+			r.scopeFunc = make(map[string]map[reflect.Type]map[reflect.Type]convertFuncClosure)
+		}
+		if len(r.scopeFunc[scope]) == 0 {
+			r.scopeFunc[scope] = make(map[reflect.Type]map[reflect.Type]convertFuncClosure)
+		}
+		if len(r.scopeFunc[scope][inType]) == 0 {
+			r.scopeFunc[scope][inType] = make(map[reflect.Type]convertFuncClosure)
+		}
+
+		// register closure
+		r.scopeFunc[scope][inType][outType] = closure
+	})
+}
+
+// AddConvFuncFor adds a conversion function that is applied only to fields
+// of the struct type of owner (or values inside them), like AddConvFuncFor(UserDto{}, f).
+// It takes precedence over other conversion functions.
+//
+// Panics if f is not a valid conversion function or owner is not a struct
+// Overwrites previous functions with the same owner and type pair
+func (m *Mapper) AddConvFuncFor(owner interface{}, f interface{}, opts ...ConvFuncOption) {
+	ownerType := reflect.TypeOf(owner)
+	for ownerType != nil && ownerType.Kind() == reflect.Ptr {
+		ownerType = ownerType.Elem()
+	}
+	if ownerType == nil || ownerType.Kind() != reflect.Struct {
+		panic("Owner of conversion function must be a struct")
+	}
+	inType, outType, closure := makeConvFuncClosure(f)
+	closure = applyConvFuncOptions(f, closure, opts)
+
+	m.updateRegistry(func(r *registry) {
+		info := convFuncInfo(inType, outType, funcName(f), opts)
+		info.Owner = ownerType
+		r.addConverterInfo(info)
+
+		// create maps
+		if len(r.ownerFunc) == 0 {
+			r.ownerFunc = make(map[reflect.Type]map[reflect.Type]map[reflect.Type]convertFuncClosure)
+		}
+		if len(r.ownerFunc[ownerType]) == 0 {
+			r.ownerFunc[ownerType] = make(map[reflect.Type]map[reflect.Type]convertFuncClosure)
+		}
+		if len(r.ownerFunc[ownerType][inType]) == 0 {
+			r.ownerFunc[ownerType][inType] = make(map[reflect.Type]convertFuncClosure)
+		}
+
+		// register closure
+		r.ownerFunc[ownerType][inType][outType] = closure
+	})
+}
+
+// AddConvFuncForUnderlying adds a conversion function that applies to all types
+// with the underlying type of its argument, i.e. func(string) ID applies to all string based types.
+// Conversion functions for exact types take precedence.
+//
+// Panics if f is not a valid conversion function or doesn't take a predeclared basic type
+// Overwrites previous functions with the same underlying type and result
+func (m *Mapper) AddConvFuncForUnderlying(f interface{}, opts ...ConvFuncOption) {
+	inType, outType, closure := makeConvFuncClosure(f)
+	if !isUniversalType(inType) || !isBasicKind(inType.Kind()) {
+		panic("Conversion function for underlying type must take a predeclared basic type")
+	}
+	closure = applyConvFuncOptions(f, closure, opts)
+
+	m.updateRegistry(func(r *registry) {
+		info := convFuncInfo(inType, outType, funcName(f), opts)
+		info.Underlying = true
+		r.addConverterInfo(info)
+
+		// create maps
+		if len(r.baseFunc) == 0 {
+			r.baseFunc = make(map[reflect.Kind]map[reflect.Type]convertFuncClosure)
+		}
+		if len(r.baseFunc[inType.Kind()]) == 0 {
+			r.baseFunc[inType.Kind()] = make(map[reflect.Type]convertFuncClosure)
+		}
+
+		// register closure converting to the underlying type
+		r.baseFunc[inType.Kind()][outType] = func(from reflect.Value, m *mapping) (reflect.Value, error) {
+			return closure(from.Convert(inType), m)
+		}
+	})
+}
+
+// Make a closure for an inspection function
+// Returns its destination and source types
+func makeInspectFuncClosure(f interface{}) (reflect.Type, reflect.Type, inspectFuncClosure) {
 	ft := reflect.TypeOf(f)
 	inType := ft.In(0).Elem()
 
 	// check if takes from
 	fromType := nilRecvRfType
-	if ft.NumIn() > 1 {
+	firstInjected := 1
+	if ft.NumIn() > 1 && ft.In(1) != fieldInfoRfType {
 		fromType = ft.In(1)
+		firstInjected = 2
 	}
 
-	// check if takes mapper
-	takesMapper := false
-	if ft.NumIn() > 2 && ft.In(2) == reflect.TypeOf(m) {
-		takesMapper = true
+	// check if to inject mapper and field info
+	var injected []reflect.Type
+	for i := firstInjected; i < ft.NumIn(); i++ {
+		if ft.In(i) != mapperPtrRfType && ft.In(i) != fieldInfoRfType {
+			panic("Bad inspection function")
+		}
+		injected = append(injected, ft.In(i))
 	}
 
 	// check if returns error
@@ -212,61 +540,101 @@ func (m *Mapper) AddInspectFunc(f interface{}) {
 		returnsError = true
 	}
 
-	// create map path
-	if len(m.postFunc) == 0 {
-		m.postFunc = make(map[reflect.Type]map[reflect.Type][]inspectFuncClosure)
-	}
-	if len(m.postFunc[inType]) == 0 {
-		m.postFunc[inType] = make(map[reflect.Type][]inspectFuncClosure)
+	closure := func(v1, v2 reflect.Value, m *mapping) error {
+		args := []reflect.Value{v1}
+		if fromType != nilRecvRfType {
+			args = append(args, v2)
+		}
+		args = append(args, m.injectArgs(injected)...)
+
+		out := reflect.ValueOf(f).Call(args)
+		if returnsError {
+			return errorFromReflectValue(out[0])
+		}
+		return nil
 	}
+	return inType, fromType, closure
+}
 
-	// register closure
-	m.postFunc[inType][fromType] = append(m.postFunc[inType][fromType],
-		func(v1, v2 reflect.Value, m *Mapper) error {
-			args := []reflect.Value{v1}
-			if fromType != nilRecvRfType {
-				args = append(args, v2)
-			}
-			if takesMapper {
-				args = append(args, reflect.ValueOf(m))
-			}
+// AddInspectFunc adds an inspection function to the Mapper
+//
+// Panics if f is not a valid inspection function
+func (m *Mapper) AddInspectFunc(f interface{}) {
+	inType, fromType, closure := makeInspectFuncClosure(f)
 
-			out := reflect.ValueOf(f).Call(args)
-			if returnsError {
-				return errorFromReflectValue(out[0])
-			}
-			return nil
-		},
-	)
+	m.updateRegistry(func(r *registry) {
+		r.hooks = append(r.hooks, HookInfo{To: inType, From: hookSourceType(fromType), Name: funcName(f)})
+
+		// create map path
+		if len(r.postFunc) == 0 {
+			r.postFunc = make(map[reflect.Type]map[reflect.Type][]inspectFuncClosure)
+		}
+		if len(r.postFunc[inType]) == 0 {
+			r.postFunc[inType] = make(map[reflect.Type][]inspectFuncClosure)
+		}
+
+		// register closure
+		r.postFunc[inType][fromType] = append(r.postFunc[inType][fromType], closure)
+	})
 }
 
 // ==================================== Mapping functions =====================
 
 // Map slices
-// Panics if arguments are not slices
-func (m *Mapper) mapSlice(toRv, fromRv reflect.Value) error {
-	toRv.Set(reflect.MakeSlice(toRv.Type(), fromRv.Len(), fromRv.Len()))
+// Panics if the destination is not a slice or the source is neither a slice nor an array
+func (m *mapping) mapSlice(toRv, fromRv reflect.Value) error {
+	if m.opts.updatePolicy == ReuseExisting && !toRv.IsNil() && toRv.Cap() >= fromRv.Len() {
+		toRv.Set(toRv.Slice(0, fromRv.Len()))
+	} else {
+		toRv.Set(reflect.MakeSlice(toRv.Type(), fromRv.Len(), fromRv.Len()))
+	}
+	j := 0
 	for i := 0; i < fromRv.Len(); i++ {
-		if err := m.mapValue(toRv.Index(i), fromRv.Index(i)); err != nil {
+		if m.opts.nilPolicy == DropNil && isNilPointer(fromRv.Index(i)) {
+			continue
+		}
+		m.pushIndex(j)
+		err := m.mapValue(toRv.Index(j), fromRv.Index(i))
+		m.popPath()
+		if err != nil {
 			return err
 		}
+		j++
+	}
+	if j < fromRv.Len() {
+		toRv.Set(toRv.Slice(0, j))
 	}
 	return nil
 }
 
 // Map maps
 // Panics if arguments are not maps
-func (m *Mapper) mapMap(dstRv, srcRv reflect.Value) error {
-	dstRv.Set(reflect.MakeMapWithSize(dstRv.Type(), srcRv.Len()))
+func (m *mapping) mapMap(dstRv, srcRv reflect.Value) error {
+	if m.opts.updatePolicy == ReuseExisting && !dstRv.IsNil() {
+		for _, key := range dstRv.MapKeys() {
+			dstRv.SetMapIndex(key, reflect.Value{})
+		}
+	} else {
+		dstRv.Set(reflect.MakeMapWithSize(dstRv.Type(), srcRv.Len()))
+	}
 	// Map values
 	mapIt := srcRv.MapRange()
 	for mapIt.Next() {
 		toKey := reflect.New(dstRv.Type().Key()).Elem()
 		toValue := reflect.New(dstRv.Type().Elem()).Elem()
-		if err := m.mapValue(toKey, mapIt.Key()); err != nil {
-			return err
+		if err := m.mapKey(toKey, mapIt.Key()); err != nil {
+			return MapKeyError{ToType: toKey.Type(), FromType: mapIt.Key().Type(), Err: err}
+		}
+		if !isHashable(toKey) {
+			return MapKeyError{ToType: toKey.Type(), FromType: mapIt.Key().Type()}
 		}
-		if err := m.mapValue(toValue, mapIt.Value()); err != nil {
+		if m.canonicalizeKey(toKey) && dstRv.MapIndex(toKey).IsValid() {
+			return KeyCollisionError{ToType: dstRv.Type(), Key: toKey.String()}
+		}
+		m.pushKey(mapIt.Key())
+		err := m.mapValue(toValue, mapIt.Value())
+		m.popPath()
+		if err != nil {
 			return err
 		}
 		dstRv.SetMapIndex(toKey, toValue)
@@ -276,20 +644,105 @@ func (m *Mapper) mapMap(dstRv, srcRv reflect.Value) error {
 
 // Map structs
 // Panics if arguments are not structs
-func (m *Mapper) mapStructs(dstRv, srcRv reflect.Value) error {
-	toFields := make(structValueMap)
-	collectStructFields(dstRv, dstRv.Type(), toFields)
+func (m *mapping) mapStructs(dstRv, srcRv reflect.Value) error {
+	owner := m.owner
+	m.owner = dstRv.Type()
+	defer func() { m.owner = owner }()
 
-	fromFields := make(structValueMap)
-	collectStructFields(srcRv, srcRv.Type(), fromFields)
+	if m.opts.updatePolicy == ReplaceExisting {
+		resetStruct(dstRv)
+	}
 
-	for fieldName, toValue := range toFields {
-		fromValue, ok := fromFields[fieldName]
-		if !ok {
+	toFields := collectStructFields(dstRv)
+	fromFields := collectStructFields(srcRv)
+
+	for i := 0; i < toFields.len(); i++ {
+		toField := toFields.at(i)
+		key := toField.key
+		if (!toField.exported && !m.opts.unexportedFields) || toField.tags.overridesSource() || toField.tags.readonly ||
+			m.opts.keepsWritten(toField.value) {
+			continue
+		}
+		var fromField structField
+		var ok bool
+		if selector, found := m.findSourceSelector(dstRv.Type(), srcRv.Type(), toField.name); found {
+			var err error
+			if fromField, ok, err = selector.selectField(srcRv); err != nil {
+				return err
+			}
+		} else if toField.tags.from != "" {
+			fromField, ok = findFromField(srcRv, toField.tags.from)
+		} else {
+			fromField, ok = m.findSourceField(fromFields, key, toField.exported)
+			if !ok {
+				if srcKey, matched := m.matchSourceKey(srcRv.Type(), key, toField.rawTag); matched {
+					fromField, ok = m.findSourceField(fromFields, srcKey, true)
+				}
+			}
+		}
+		if !ok || fromField.tags.to != "" || fromField.tags.ignoreOut {
+			if !toField.tags.hasDefault {
+				if toField.tags.required && !toField.tags.derived() {
+					m.pushField(toField.name)
+					m.addMissingRequired()
+					m.popPath()
+				}
+				continue
+			}
+			// mapped from the default value
+			fromField = structField{}
+		}
+		if !toField.exported {
+			toField.value = exposeField(toField.value)
+		}
+		m.pushField(toField.name)
+		field := m.field
+		m.field = toField
+		var err error
+		if m.written != nil && isLeafField(toField.value) {
+			err = m.mapSourceField(toField, fromField)
+		} else {
+			err = m.mapField(toField, fromField)
+		}
+		m.field = field
+		m.popPath()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := m.mapToPaths(dstRv, srcRv); err != nil {
+		return err
+	}
+
+	// Fill derived fields, unless they have a source, and injected, constant, composed and remain fields
+	for i := 0; i < toFields.len(); i++ {
+		toField := toFields.at(i)
+		if !toField.tags.derived() || !toField.exported {
+			continue
+		}
+		if fromFields.has(toField.key) && !toField.tags.overridesSource() {
 			continue
 		}
-		err := m.mapValue(toValue, fromValue)
+		m.pushField(toField.name)
+		var err error
+		switch {
+		case toField.tags.keysOf != "":
+			err = m.mapKeysOf(toField.value, srcRv, toFields, toField.tags.keysOf)
+		case toField.tags.present != "":
+			err = m.mapPresence(toField.value, srcRv, fromFields, toField.tags.present)
+		case toField.tags.inject != "":
+			err = m.mapInjected(toField.value, toField.tags.inject)
+		case toField.tags.isConst:
+			err = m.mapConst(toField.value, toField.tags)
+		case toField.tags.template != "":
+			err = m.mapTemplate(toField.value, srcRv, toField.tags)
+		case toField.tags.remain:
+			err = m.mapRemain(toField.value, srcRv, toFields, fromFields)
+		}
+		m.popPath()
 		if err != nil {
+			m.recordErrorPath()
 			return err
 		}
 	}
@@ -297,14 +750,117 @@ func (m *Mapper) mapStructs(dstRv, srcRv reflect.Value) error {
 	return nil
 }
 
+// Map source fields with the to tag into nested destination fields,
+// allocating nil pointers on the way
+func (m *mapping) mapToPaths(dstRv, srcRv reflect.Value) error {
+	for _, info := range structFieldInfos(srcRv.Type()) {
+		if info.tags.to == "" || !info.exported || info.tags.ignoreOut {
+			continue
+		}
+		toField, err := allocStructFieldPath(dstRv, info.tags.to)
+		if err == nil && !toField.exported {
+			err = FieldNotFoundError{Type: dstRv.Type(), Field: info.tags.to}
+		}
+		if err != nil {
+			return err
+		}
+		if toField.tags.readonly {
+			continue
+		}
+		fromField := structField{name: info.name, value: srcRv.FieldByIndex(info.index), tags: info.tags,
+			rawTag: info.rawTag, exported: true}
+		segments := strings.Split(info.tags.to, ".")
+		for _, segment := range segments {
+			m.pushField(segment)
+		}
+		err = m.mapField(toField, fromField)
+		m.path = m.path[:len(m.path)-len(segments)]
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Map struct fields, taking their tags into account.
+// Missing sources of fields with a default value are marked with an invalid value.
+func (m *mapping) mapField(dst, src structField) error {
+	if dst.tags.hasDefault && (!src.value.IsValid() || src.value.IsZero()) {
+		value, err := parseTagValue(dst.value.Type(), dst.tags.defaultValue, false)
+		if err != nil {
+			m.recordErrorPath()
+			return err
+		}
+		src = structField{name: dst.name, value: value, exported: true}
+	}
+	// partial updates keep destination values of empty sources
+	if dst.tags.omitEmpty && isOmittable(src.value) {
+		return nil
+	}
+	switch {
+	case dst.tags.index != "":
+		return m.mapSliceToIndex(dst.value, src.value, dst.tags.index)
+	case dst.tags.groupBy != "":
+		return m.mapSliceToGroups(dst.value, src.value, dst.tags.groupBy)
+	}
+	var err error
+	switch {
+	case dst.tags.unique != "" || len(dst.tags.filter) > 0:
+		err = m.mapElements(dst.value, src.value, dst.tags)
+	case dst.tags.unwrap != NoUnwrap:
+		err = m.unwrapSlice(dst.value, src.value, dst.tags.unwrap)
+	case dst.tags.wrap:
+		err = m.wrapValue(dst.value, src.value)
+	case dst.tags.json:
+		err = m.mapJSON(dst.value, src.value)
+	case dst.tags.flags != "" || dst.tags.flag != "":
+		err = m.mapFlags(dst.value, src.value, dst.tags)
+	default:
+		err = m.mapValue(dst.value, src.value)
+	}
+	if err == nil && dst.tags.sort {
+		err = m.sortSlice(dst.value, dst.tags.sortKey)
+	}
+	if err == nil && dst.tags.precision != "" {
+		err = m.roundPrecision(dst.value, dst.tags.precision)
+	}
+	if err == nil {
+		err = m.validateField(dst.value, dst.tags)
+	}
+	if err != nil {
+		m.recordErrorPath()
+	}
+	return err
+}
+
+// Map whether a source field is set, i.e. a non nil pointer, slice, map or interface
+// or a non zero value otherwise
+func (m *mapping) mapPresence(dstRv, srcRv reflect.Value, fromFields structFieldMap, name string) error {
+	field, ok := fromFields.get(name)
+	if !ok {
+		return FieldNotFoundError{Type: srcRv.Type(), Field: name}
+	}
+	var present bool
+	switch field.value.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		present = !field.value.IsNil()
+	default:
+		present = !field.value.IsZero()
+	}
+	return m.mapValue(dstRv, reflect.ValueOf(present))
+}
+
 // Map map values to slice
 // Panics if arguments are not slice and map accordingly
-func (m *Mapper) mapMapToSlice(dstRv, srcRv reflect.Value) error {
+func (m *mapping) mapMapToSlice(dstRv, srcRv reflect.Value) error {
 	dstRv.Set(reflect.MakeSlice(dstRv.Type(), srcRv.Len(), srcRv.Len()))
 	i := 0
 	mapIt := srcRv.MapRange()
 	for mapIt.Next() {
-		if err := m.mapValue(dstRv.Index(i), mapIt.Value()); err != nil {
+		m.pushIndex(i)
+		err := m.mapValue(dstRv.Index(i), mapIt.Value())
+		m.popPath()
+		if err != nil {
 			return err
 		}
 		i++
@@ -314,7 +870,7 @@ func (m *Mapper) mapMapToSlice(dstRv, srcRv reflect.Value) error {
 
 // Map a map of slices to slice
 // Panics of arguments are not a map of slices and a slice accordingly
-func (m *Mapper) mapMapSlicesToSlice(dstRv, srcRv reflect.Value) error {
+func (m *mapping) mapMapSlicesToSlice(dstRv, srcRv reflect.Value) error {
 	// calculate length
 	sumLen := 0
 	mapIt := srcRv.MapRange()
@@ -329,7 +885,10 @@ func (m *Mapper) mapMapSlicesToSlice(dstRv, srcRv reflect.Value) error {
 	for mapIt.Next() {
 		mapSlice := mapIt.Value()
 		for j := 0; j < mapSlice.Len(); i, j = i+1, j+1 {
-			if err := m.mapValue(dstRv.Index(i), mapSlice.Index(j)); err != nil {
+			m.pushIndex(i)
+			err := m.mapValue(dstRv.Index(i), mapSlice.Index(j))
+			m.popPath()
+			if err != nil {
 				return err
 			}
 		}
@@ -338,17 +897,96 @@ func (m *Mapper) mapMapSlicesToSlice(dstRv, srcRv reflect.Value) error {
 	return nil
 }
 
+// Map a nil source pointer according to the nil policy
+func (m *mapping) mapNil(dstRv, srcRv reflect.Value) error {
+	switch m.opts.nilPolicy {
+	case ZeroNil:
+		dstRv.Set(reflect.Zero(dstRv.Type()))
+	case RejectNil:
+		return NilValueError{
+			ToType:   dstRv.Type(),
+			FromType: srcRv.Type(),
+		}
+	default:
+		m.skipped = true
+	}
+	return nil
+}
+
+// Map an unwrapped source to the same destination.
+// Marks the destination as inspected and possibly skipped for the enclosing mapValue call.
+func (m *mapping) mapUnwrapped(dstRv, srcRv reflect.Value) error {
+	err := m.mapValue(dstRv, srcRv)
+	m.unwrapped, m.skipped = true, m.lastSkipped
+	return err
+}
+
+// Map an assignable value without sharing references
+// Panics if src is not assignable to dst
+func (m *mapping) copyValue(dstRv, srcRv reflect.Value) error {
+	switch srcRv.Type().Kind() {
+	case reflect.Ptr:
+		if srcRv.IsNil() {
+			dstRv.Set(reflect.Zero(dstRv.Type()))
+			return nil
+		}
+		ptr := reflect.New(dstRv.Type().Elem())
+		if err := m.mapValue(ptr.Elem(), srcRv.Elem()); err != nil {
+			return err
+		}
+		dstRv.Set(ptr)
+	case reflect.Slice:
+		if srcRv.IsNil() {
+			dstRv.Set(reflect.Zero(dstRv.Type()))
+			return nil
+		}
+		return m.mapSlice(dstRv, srcRv)
+	case reflect.Map:
+		if srcRv.IsNil() {
+			dstRv.Set(reflect.Zero(dstRv.Type()))
+			return nil
+		}
+		return m.mapMap(dstRv, srcRv)
+	case reflect.Struct:
+		// assign first to keep unexported and ignored fields
+		dstRv.Set(srcRv)
+		return m.mapStructs(dstRv, srcRv)
+	case reflect.Array:
+		dstRv.Set(srcRv)
+		for i := 0; i < srcRv.Len(); i++ {
+			m.pushIndex(i)
+			err := m.mapValue(dstRv.Index(i), srcRv.Index(i))
+			m.popPath()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Try to map any value
-func (m *Mapper) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
+func (m *mapping) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
 	tk, fk := dstRv.Type().Kind(), srcRv.Type().Kind()
 
-	// Defer inspect functions
-	defer func() {
+	// Defer inspect functions, they receive the source before decode hooks.
+	// They run once per destination value, even if the source is unwrapped (see Stage).
+	defer func(srcRv reflect.Value) {
+		unwrapped := m.unwrapped
+		m.unwrapped = false
+		if returnError == nil && m.opts.inspects(m.skipped) {
+			returnError = m.runInspectFuncs(dstRv, srcRv, unwrapped)
+		}
+		m.lastSkipped, m.skipped = m.skipped, false
 		if returnError != nil {
-			return
+			m.recordErrorPath()
 		}
-		returnError = m.runInspectFuncs(dstRv, srcRv)
-	}()
+	}(srcRv)
+
+	// Check cancellation and report progress
+	if err := m.step(); err != nil {
+		return err
+	}
 
 	// 1. Check conversion functions
 	converted, err := m.runConvFuncs(dstRv, srcRv)
@@ -356,36 +994,135 @@ func (m *Mapper) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
 		return err
 	}
 
-	// 2. Check direct assignment
+	// Run decode hooks
+	srcRv, ok, err := m.runDecodeHooks(dstRv, srcRv)
+	if !ok {
+		return err
+	}
+	fk = srcRv.Kind()
+
+	// Check Mappable implementations
+	if mapped, err := m.runMappable(dstRv, srcRv); mapped {
+		return err
+	}
+
+	// Copy json.RawMessage values
+	if copyRawMessage(dstRv, srcRv) {
+		return nil
+	}
+
+	// Handle functions, channels and unsafe pointers
+	if (isOpaqueKind(fk) || isOpaqueKind(tk)) && m.opts.opaquePolicy != ShareOpaque {
+		if m.opts.opaquePolicy == RejectOpaque {
+			return OpaqueValueError{ToType: dstRv.Type(), FromType: srcRv.Type()}
+		}
+		m.skipped = true
+		return nil
+	}
+
+	// 2. Check direct assignment, readonly and written fields must not be overwritten,
+	// ignore_out fields must not be read and fields populated by multiple sources are tracked one by one
+	if tk == reflect.Struct && fk == reflect.Struct && (hasReadonlyFields(dstRv.Type()) ||
+		hasIgnoreOutFields(srcRv.Type()) || m.opts.mergesWritten(dstRv) || m.written != nil) {
+		return m.mapStructs(dstRv, srcRv)
+	}
+	if tk == reflect.Ptr && !dstRv.IsNil() && (m.opts.mergesWritten(dstRv.Elem()) || m.written != nil && dstRv.Elem().Kind() == reflect.Struct) {
+		return m.mapValue(dstRv.Elem(), srcRv)
+	}
 	if srcRv.Type().AssignableTo(dstRv.Type()) {
+		// don't produce typed nils
+		if tk == reflect.Interface && fk == reflect.Ptr && srcRv.IsNil() {
+			return m.mapNil(dstRv, srcRv)
+		}
+		if m.copiesKind(fk) {
+			return m.copyValue(dstRv, srcRv)
+		}
 		dstRv.Set(srcRv)
 		return
 	}
 
-	// 3. Check conversion
-	if srcRv.Type().ConvertibleTo(dstRv.Type()) {
-		dstRv.Set(srcRv.Convert(dstRv.Type()))
+	// 3. Check number formats, default converters of kinds and conversion
+	if formatted, err := m.formatNumber(dstRv, srcRv); formatted {
+		return err
+	}
+	if converted, err := m.runKindConverter(dstRv, srcRv); converted {
+		return err
+	}
+	if canConvert(dstRv.Type(), srcRv.Type()) {
+		if m.copiesKind(fk) && fk == tk {
+			return m.copyValue(dstRv, srcRv.Convert(dstRv.Type()))
+		}
+		if tk == reflect.Struct && srcRv.CanAddr() {
+			// convert pointers to identical shapes, which doesn't allocate a copy
+			dstRv.Set(srcRv.Addr().Convert(reflect.PtrTo(dstRv.Type())).Elem())
+			return
+		}
+		dstRv.Set(m.opts.roundFloat(tk, srcRv).Convert(dstRv.Type()))
 		return
 	}
 
 	// 4. Handle pointers by dereferencing from
 	if fk == reflect.Ptr {
-		// Skip null pointers
 		if srcRv.IsNil() {
-			return nil
+			return m.mapNil(dstRv, srcRv)
 		}
-		return m.mapValue(dstRv, srcRv.Elem())
+		return m.mapUnwrapped(dstRv, srcRv.Elem())
+	}
+
+	// 4. Handle interfaces by their dynamic values
+	if fk == reflect.Interface {
+		if srcRv.IsNil() {
+			return m.mapNil(dstRv, srcRv)
+		}
+		return m.mapUnwrapped(dstRv, srcRv.Elem())
+	}
+
+	// 4. Handle sync.Map and atomic containers by snapshots
+	if snapshot, ok, err := loadSnapshot(srcRv); ok {
+		if err != nil {
+			return err
+		}
+		return m.mapUnwrapped(dstRv, snapshot)
+	}
+
+	// 4. Handle result envelopes by their values
+	if mapped, err := m.mapResult(dstRv, srcRv); mapped {
+		return err
+	}
+
+	// 4. Handle optional wrappers
+	if mapped, err := m.mapOptional(dstRv, srcRv); mapped {
+		return err
 	}
 
 	// 5. Handle pointers by dereferencing to
 	if tk == reflect.Ptr {
-		// Allocate new value if nil
-		if dstRv.IsNil() {
+		if m.opts.emptyAsNil && isEmptyValue(srcRv) {
+			dstRv.Set(reflect.Zero(dstRv.Type()))
+			return nil
+		}
+		// Allocate new value if nil or not to be reused
+		if dstRv.IsNil() || !m.opts.reusesPointers() {
 			dstRv.Set(reflect.New(dstRv.Type().Elem()))
 		}
 		return m.mapValue(dstRv.Elem(), srcRv)
 	}
 
+	// 6. Handle ordered maps like maps, or by their values in order
+	if (tk == reflect.Map || tk == reflect.Slice) && isOrderedMap(srcRv.Type()) {
+		return m.mapValue(dstRv, orderedMapEntries(srcRv, tk == reflect.Slice))
+	}
+
+	// 6. Handle custom collections
+	if fk != reflect.Slice && isSourceCollection(srcRv.Type()) {
+		if tk == reflect.Slice || isDestCollection(dstRv.Type()) {
+			return m.mapValue(dstRv, collectionToSlice(srcRv))
+		}
+	}
+	if fk == reflect.Slice && isDestCollection(dstRv.Type()) {
+		return m.mapSliceToCollection(dstRv, srcRv)
+	}
+
 	// 6. Handle sructs
 	if tk == reflect.Struct && fk == reflect.Struct {
 		return m.mapStructs(dstRv, srcRv)
@@ -401,6 +1138,11 @@ func (m *Mapper) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
 		return m.mapMap(dstRv, srcRv)
 	}
 
+	// 8. Handle structs to maps
+	if isStructToMap(dstRv.Type(), srcRv.Type()) {
+		return m.mapStructToMap(dstRv, srcRv)
+	}
+
 	// 9. Handle map to slice
 	if tk == reflect.Slice && fk == reflect.Map {
 		err := m.mapMapToSlice(dstRv, srcRv)
@@ -410,6 +1152,7 @@ func (m *Mapper) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
 		if errors.As(err, &NoValidMappingError{}) && mapElemK == reflect.Slice {
 			// dont propagate errors
 			if errFlatten := m.mapMapSlicesToSlice(dstRv, srcRv); errFlatten == nil {
+				m.clearErrorPath()
 				return
 			}
 		}
@@ -417,6 +1160,16 @@ func (m *Mapper) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
 		return err
 	}
 
+	// 10. Handle slice to single value
+	if fk == reflect.Slice && m.opts.unwrapPolicy != NoUnwrap {
+		return m.unwrapSlice(dstRv, srcRv, m.opts.unwrapPolicy)
+	}
+
+	// 11. Handle single value to slice
+	if tk == reflect.Slice && m.opts.wrapValues {
+		return m.wrapValue(dstRv, srcRv)
+	}
+
 	return NoValidMappingError{
 		ToType:   dstRv.Type(),
 		FromType: srcRv.Type(),
@@ -426,12 +1179,17 @@ func (m *Mapper) mapValue(dstRv, srcRv reflect.Value) (returnError error) {
 // ==================================== Public helpers ========================
 
 // Map transfers values from src to dst
-func (m *Mapper) Map(dst, src interface{}) error {
-	return m.mapValue(reflectValueRemovePtr(dst), reflectValueRemovePtr(src))
+// Options apply only to this call
+func (m *Mapper) Map(dst, src interface{}, opts ...Option) error {
+	dstRv, srcRv := reflectValueRemovePtr(dst), reflectValueRemovePtr(src)
+	mp := m.newMapping(opts...)
+	return mp.track(dstRv, srcRv, func() error {
+		return mp.mapValue(dstRv, srcRv)
+	})
 }
 
 // Map transfers values from src to dst
-func Map(dst, src interface{}) error {
+func Map(dst, src interface{}, opts ...Option) error {
 	m := Mapper{}
-	return m.Map(dst, src)
+	return m.Map(dst, src, opts...)
 }
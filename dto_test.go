@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -244,6 +245,238 @@ func TestConversionFunc(t *testing.T) {
 	assert.Equal(t, len(testUser.Password), outUser.Password)
 }
 
+// Conversion functions for all types with the same underlying type
+func TestConversionFuncForUnderlying(t *testing.T) {
+	type UserID string
+	type OrderID string
+	type ID struct {
+		Value string
+	}
+	from := struct {
+		User  UserID
+		Order OrderID
+		Name  string
+	}{"u1", "o1", "Bob"}
+	var out struct {
+		User  ID
+		Order ID
+		Name  ID
+	}
+
+	m := Mapper{}
+	m.AddConvFuncForUnderlying(func(s string) ID {
+		return ID{Value: s}
+	})
+	m.AddConvFunc(func(id OrderID) ID {
+		return ID{Value: "order:" + string(id)}
+	})
+
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, ID{"u1"}, out.User)
+	assert.Equal(t, ID{"order:o1"}, out.Order)
+	assert.Equal(t, ID{"Bob"}, out.Name)
+
+	assert.Panics(t, func() {
+		m.AddConvFuncForUnderlying(func(id UserID) ID { return ID{} })
+	})
+}
+
+// Scoped conversion functions selected per Map call
+func TestScopedConversionFunc(t *testing.T) {
+	type PriceDto struct {
+		Price string
+	}
+	m := Mapper{}
+	m.AddConvFunc(func(p float32) string {
+		return fmt.Sprintf("%.2f", p)
+	})
+	m.AddScopedConvFunc("de", func(p float32) string {
+		return fmt.Sprintf("%.2f €", p)
+	})
+	m.AddScopedConvFunc("us", func(p float32) string {
+		return fmt.Sprintf("$%.2f", p)
+	})
+
+	var out PriceDto
+	err := m.Map(&out, commonProducts[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "9.40", out.Price)
+
+	err = m.Map(&out, commonProducts[0], WithScope("de"))
+	assert.Nil(t, err)
+	assert.Equal(t, "9.40 €", out.Price)
+
+	err = m.Map(&out, commonProducts[0], WithScope("us"))
+	assert.Nil(t, err)
+	assert.Equal(t, "$9.40", out.Price)
+
+	err = m.Map(&out, commonProducts[0], WithScope("fr"))
+	assert.Nil(t, err)
+	assert.Equal(t, "9.40", out.Price)
+}
+
+// Slices of pointers and values map into each other, nil elements follow the nil policy
+func TestPointerSlices(t *testing.T) {
+	type ProductDto struct {
+		Name  string
+		Price float32
+		Link  string
+	}
+	shirt, shoes := commonProducts[0], commonProducts[1]
+	shirtDto := ProductDto{Name: shirt.Name, Price: shirt.Price}
+	shoesDto := ProductDto{Name: shoes.Name, Price: shoes.Price}
+
+	{
+		var out []ProductDto
+		err := Map(&out, []*Product{&shirt, nil, &shoes})
+		assert.Nil(t, err)
+		assert.Equal(t, []ProductDto{shirtDto, {}, shoesDto}, out)
+	}
+	{
+		var out []*ProductDto
+		err := Map(&out, []Product{shirt, shoes})
+		assert.Nil(t, err)
+		assert.Equal(t, []*ProductDto{&shirtDto, &shoesDto}, out)
+	}
+	{
+		var out []*ProductDto
+		err := Map(&out, []*Product{&shirt, nil})
+		assert.Nil(t, err)
+		assert.Equal(t, []*ProductDto{&shirtDto, nil}, out)
+	}
+	{
+		var out []ProductDto
+		err := Map(&out, []*Product{&shirt, nil}, WithNilPolicy(RejectNil))
+		assert.ErrorAs(t, err, &NilValueError{})
+	}
+	{
+		var out []interface{}
+		err := Map(&out, []*Product{&shirt, nil})
+		assert.Nil(t, err)
+		assert.Len(t, out, 2)
+		assert.Same(t, &shirt, out[0])
+		assert.True(t, out[1] == nil)
+	}
+}
+
+// Struct map keys are mapped recursively and by conversion functions
+func TestMapStructKeys(t *testing.T) {
+	type Key struct {
+		Country string
+		Year    int
+	}
+	type KeyDto struct {
+		Country string
+		Year    int64
+	}
+	from := map[Key]int{{"US", 2020}: 1, {"UK", 2021}: 2}
+
+	var out map[KeyDto]int
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, map[KeyDto]int{{"US", 2020}: 1, {"UK", 2021}: 2}, out)
+
+	m := Mapper{}
+	m.AddConvFunc(func(k Key) string {
+		return fmt.Sprintf("%v-%v", k.Country, k.Year)
+	})
+	var outString map[string]int
+	err = m.Map(&outString, from)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"US-2020": 1, "UK-2021": 2}, outString)
+}
+
+// Failed and unhashable map keys are reported with their types
+func TestMapKeyErrors(t *testing.T) {
+	type Key struct {
+		Country string
+	}
+	type AnyKey struct {
+		Country interface{}
+	}
+
+	var out map[AnyKey]int
+	err := Map(&out, map[Key]int{{"US"}: 1})
+	assert.Nil(t, err)
+
+	m := Mapper{}
+	m.AddConvFunc(func(country string) interface{} {
+		return []string{country}
+	})
+	err = m.Map(&out, map[Key]int{{"US"}: 1})
+	assert.Equal(t, MapKeyError{ToType: reflect.TypeOf(AnyKey{}), FromType: reflect.TypeOf(Key{})}, err)
+
+	var outInt map[int]int
+	err = Map(&outInt, map[Key]int{{"US"}: 1})
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+	assert.ErrorAs(t, err, &MapKeyError{})
+}
+
+// Presence flags are derived from nullable source fields with the present tag
+func TestPresentTag(t *testing.T) {
+	type Entity struct {
+		Name      string
+		DeletedAt *time.Time
+		Tags      []string
+	}
+	type EntityDto struct {
+		Name      string
+		IsDeleted bool  `dto:"present=DeletedAt"`
+		HasTags   *bool `dto:"present=Tags"`
+		HasName   bool  `dto:"present=Name"`
+	}
+	deleted := time.Now()
+
+	var out EntityDto
+	err := Map(&out, Entity{Name: "Bob", DeletedAt: &deleted})
+	assert.Nil(t, err)
+	assert.True(t, out.IsDeleted)
+	assert.False(t, *out.HasTags)
+	assert.True(t, out.HasName)
+
+	err = Map(&out, Entity{Tags: []string{}})
+	assert.Nil(t, err)
+	assert.False(t, out.IsDeleted)
+	assert.True(t, *out.HasTags)
+	assert.False(t, out.HasName)
+
+	var outMissing struct {
+		IsArchived bool `dto:"present=ArchivedAt"`
+	}
+	err = Map(&outMissing, Entity{})
+	assert.ErrorAs(t, err, &FieldNotFoundError{})
+}
+
+// Conversion functions for an owner apply only to its fields
+func TestOwnerConversionFunc(t *testing.T) {
+	type PriceDto struct {
+		Price string
+	}
+	type OfferDto struct {
+		Price  string
+		Prices []string
+		Base   PriceDto
+	}
+	m := Mapper{}
+	m.AddConvFunc(func(p float32) string {
+		return fmt.Sprintf("%.2f", p)
+	})
+	m.AddConvFuncFor(&OfferDto{}, func(p float32) string {
+		return fmt.Sprintf("%.0f", p)
+	})
+
+	from := struct {
+		Price  float32
+		Prices []float32
+		Base   Product
+	}{9.4, []float32{17.3}, commonProducts[0]}
+	var out OfferDto
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, OfferDto{Price: "9", Prices: []string{"17"}, Base: PriceDto{Price: "9.40"}}, out)
+}
+
 // Inspect functions without errors and with mapper injection that change data
 func TestInspectFunc(t *testing.T) {
 	type ProductDTO struct {
@@ -343,6 +576,43 @@ func TestPointerCases(t *testing.T) {
 	}
 }
 
+// Multi level pointers are unwrapped and allocated at every level
+func TestMultiLevelPointers(t *testing.T) {
+	type SubProduct struct {
+		Name string
+	}
+	prod := &commonProducts[0]
+	{
+		var from = struct{ Prod **Product }{Prod: &prod}
+		var to struct{ Prod SubProduct }
+		err := Map(&to, from)
+		assert.Nil(t, err)
+		assert.Equal(t, prod.Name, to.Prod.Name)
+	}
+	{
+		var from = struct{ Prod Product }{Prod: *prod}
+		var to struct{ Prod ***SubProduct }
+		err := Map(&to, from)
+		assert.Nil(t, err)
+		assert.Equal(t, prod.Name, (***to.Prod).Name)
+	}
+	{
+		var from = struct{ Prod **Product }{Prod: &prod}
+		var to struct{ Prod **SubProduct }
+		err := Map(&to, from)
+		assert.Nil(t, err)
+		assert.Equal(t, prod.Name, (**to.Prod).Name)
+	}
+	{
+		var nilProd *Product
+		var from = struct{ Prod **Product }{Prod: &nilProd}
+		var to struct{ Prod **SubProduct }
+		err := Map(&to, from)
+		assert.Nil(t, err)
+		assert.Nil(t, to.Prod)
+	}
+}
+
 func TestStructureTagIgnoreCase(t *testing.T) {
 	order := Order{Id: "test"}
 	var outOrder OrderDto
@@ -350,6 +620,229 @@ func TestStructureTagIgnoreCase(t *testing.T) {
 	assert.Equal(t, order.Id, outOrder.Id)
 }
 
+// Fields with the name tag are matched by another name on either side
+func TestStructureTagName(t *testing.T) {
+	type Customer struct {
+		Name  string `dto:"name=CustomerName"`
+		Email string
+	}
+	type CustomerDto struct {
+		CustomerName string
+		Mail         string `dto:"name=Email"`
+	}
+	dto := CustomerDto{}
+	err := Map(&dto, Customer{Name: "Bob", Email: "bob@mail.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, CustomerDto{CustomerName: "Bob", Mail: "bob@mail.com"}, dto)
+
+	// and back
+	customer := Customer{}
+	err = Map(&customer, dto)
+	assert.Nil(t, err)
+	assert.Equal(t, Customer{Name: "Bob", Email: "bob@mail.com"}, customer)
+}
+
+// Fields with the from tag are mapped from nested source fields
+func TestStructureTagFrom(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Customer struct {
+		Name    string
+		Address *Address
+	}
+	type CustomerDto struct {
+		Name string
+		City string `dto:"from=Address.City"`
+	}
+	dto := CustomerDto{}
+	err := Map(&dto, Customer{Name: "Bob", Address: &Address{City: "Berlin"}})
+	assert.Nil(t, err)
+	assert.Equal(t, CustomerDto{Name: "Bob", City: "Berlin"}, dto)
+
+	// nil pointers on the way are skipped
+	dto = CustomerDto{City: "Paris"}
+	err = Map(&dto, Customer{Name: "Bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, CustomerDto{Name: "Bob", City: "Paris"}, dto)
+
+	// the source field counts as mapped
+	mapper := Mapper{}
+	diff, err := mapper.DiffFields(CustomerDto{}, Customer{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+
+	// missing paths are reported
+	type BadDto struct {
+		City string `dto:"from=Address.Town"`
+	}
+	mapper.RegisterPair(BadDto{}, Customer{})
+	err = mapper.Validate()
+	var ce ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.Len(t, ce, 1)
+	assert.Equal(t, FieldNotFoundError{Type: reflect.TypeOf(Customer{}), Field: "Address.Town"}, ce[0].Err)
+}
+
+// Alternatives of the from tag are tried in order
+func TestStructureTagFromFallback(t *testing.T) {
+	type UserV1 struct {
+		Name string
+	}
+	type UserV2 struct {
+		UserName string
+		NickName string
+	}
+	type UserDto struct {
+		Name string `dto:"from=NickName|UserName|Name"`
+	}
+	dto := UserDto{}
+	err := Map(&dto, UserV1{Name: "Bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob", dto.Name)
+
+	err = Map(&dto, UserV2{UserName: "bob42", NickName: "Bobby"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bobby", dto.Name)
+
+	// zero values are skipped
+	err = Map(&dto, UserV2{UserName: "bob42"})
+	assert.Nil(t, err)
+	assert.Equal(t, "bob42", dto.Name)
+
+	// unless all are zero
+	err = Map(&dto, UserV2{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", dto.Name)
+
+	mapper := Mapper{}
+	diff, err := mapper.DiffFields(UserDto{}, UserV2{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+	mapper.RegisterPair(UserDto{}, UserV1{})
+	assert.Nil(t, mapper.Validate())
+}
+
+// Source fields with the to tag are mapped into nested destination fields
+func TestStructureTagTo(t *testing.T) {
+	type Profile struct {
+		Bio     string
+		Website string
+	}
+	type User struct {
+		Name    string
+		Profile *Profile
+	}
+	type UserDto struct {
+		Name string
+		Bio  string `dto:"to=Profile.Bio"`
+	}
+	user := User{}
+	err := Map(&user, UserDto{Name: "Bob", Bio: "Gopher"})
+	assert.Nil(t, err)
+	assert.Equal(t, User{Name: "Bob", Profile: &Profile{Bio: "Gopher"}}, user)
+
+	// existing values are kept
+	user.Profile.Website = "go.dev"
+	err = Map(&user, UserDto{Bio: "Rustacean"})
+	assert.Nil(t, err)
+	assert.Equal(t, &Profile{Bio: "Rustacean", Website: "go.dev"}, user.Profile)
+
+	// the destination field counts as populated
+	mapper := Mapper{}
+	diff, err := mapper.DiffFields(User{}, UserDto{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+
+	// missing paths are reported
+	type BadDto struct {
+		Bio string `dto:"to=Profile.About"`
+	}
+	err = Map(&user, BadDto{})
+	assert.Equal(t, FieldNotFoundError{Type: reflect.TypeOf(Profile{}), Field: "About"}, err)
+	mapper.RegisterPair(User{}, BadDto{})
+	err = mapper.Validate()
+	var ce ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.Len(t, ce, 1)
+	assert.Equal(t, FieldNotFoundError{Type: reflect.TypeOf(User{}), Field: "Profile.About"}, ce[0].Err)
+}
+
+// Paths of from and to tags are resolved by field names, even if fields are renamed
+func TestStructureTagPathNames(t *testing.T) {
+	type Profile struct {
+		Bio string `dto:"name=About"`
+	}
+	type User struct {
+		Profile Profile `dto:"name=Details"`
+	}
+	type UserDto struct {
+		Bio string `dto:"to=Profile.Bio"`
+	}
+	type UserView struct {
+		Bio string `dto:"from=Profile.Bio"`
+	}
+	user := User{}
+	err := Map(&user, UserDto{Bio: "Gopher"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Gopher", user.Profile.Bio)
+
+	view := UserView{}
+	err = Map(&view, user)
+	assert.Nil(t, err)
+	assert.Equal(t, "Gopher", view.Bio)
+
+	// keys set by name tags don't resolve
+	type KeyDto struct {
+		Bio string `dto:"to=Details.About"`
+	}
+	err = Map(&user, KeyDto{})
+	assert.Equal(t, FieldNotFoundError{Type: reflect.TypeOf(User{}), Field: "Details"}, err)
+}
+
+// Fields of struct fields with the squash tag are promoted like embedded fields
+func TestStructureTagSquash(t *testing.T) {
+	type Audit struct {
+		CreatedBy string
+		UpdatedBy string
+	}
+	type Document struct {
+		Title string
+		Audit Audit `dto:"squash"`
+	}
+	type DocumentDto struct {
+		Title     string
+		CreatedBy string
+		UpdatedBy string
+	}
+	dto := DocumentDto{}
+	err := Map(&dto, Document{Title: "Spec", Audit: Audit{CreatedBy: "alice", UpdatedBy: "bob"}})
+	assert.Nil(t, err)
+	assert.Equal(t, DocumentDto{Title: "Spec", CreatedBy: "alice", UpdatedBy: "bob"}, dto)
+
+	// and populated from flat sources
+	doc := Document{}
+	err = Map(&doc, dto)
+	assert.Nil(t, err)
+	assert.Equal(t, Document{Title: "Spec", Audit: Audit{CreatedBy: "alice", UpdatedBy: "bob"}}, doc)
+
+	mapper := Mapper{}
+	diff, err := mapper.DiffFields(DocumentDto{}, Document{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+
+	// only structs can be squashed
+	type BadDto struct {
+		Title string `dto:"squash"`
+	}
+	mapper.RegisterPair(BadDto{}, Document{})
+	err = mapper.Validate()
+	var ce ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.Len(t, ce, 1)
+	assert.Equal(t, TagError{Tag: "squash", Type: stringRfType, Reason: "not a struct"}, ce[0].Err)
+}
+
 // ==================================== Benchmarks ============================
 
 type benchCart = struct {
@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+)
+
+// PageInfo describes a page of a collection
+type PageInfo struct {
+	Page    int
+	PerPage int
+	Total   int
+}
+
+// PageFields names the fields of page envelope structs
+// Empty names are not populated
+type PageFields struct {
+	Items   string
+	Total   string
+	Page    string
+	PerPage string
+	Pages   string
+}
+
+// DefaultPageFields are used by MapPage unless configured with WithPageFields
+var DefaultPageFields = PageFields{
+	Items:   "Items",
+	Total:   "Total",
+	Page:    "Page",
+	PerPage: "PerPage",
+	Pages:   "Pages",
+}
+
+// WithPageFields sets the field names used by MapPage
+func WithPageFields(fields PageFields) Option {
+	return func(o *options) {
+		o.pageFields = &fields
+	}
+}
+
+// Total number of pages
+func (pi PageInfo) pages() int {
+	if pi.PerPage <= 0 {
+		return 0
+	}
+	return (pi.Total + pi.PerPage - 1) / pi.PerPage
+}
+
+// MapPage maps src into the items field of the dst envelope struct
+// and populates its paging fields from page.
+// Paging fields missing in dst are skipped.
+func (m *Mapper) MapPage(dst interface{}, src interface{}, page PageInfo, opts ...Option) error {
+	dstRv, srcRv := reflectValueRemovePtr(dst), reflectValueRemovePtr(src)
+	mp := m.newMapping(opts...)
+	return mp.track(dstRv, srcRv, func() error {
+		return mp.mapPage(dstRv, srcRv, page)
+	})
+}
+
+// Map a page into an envelope
+func (m *mapping) mapPage(dstRv, srcRv reflect.Value, page PageInfo) error {
+	fields := DefaultPageFields
+	if m.opts.pageFields != nil {
+		fields = *m.opts.pageFields
+	}
+
+	if dstRv.Kind() != reflect.Struct {
+		return NoValidMappingError{ToType: dstRv.Type(), FromType: reflect.TypeOf(page)}
+	}
+	dstFields := collectStructFields(dstRv)
+
+	items, ok := dstFields.get(fields.Items)
+	if !ok {
+		return FieldNotFoundError{Type: dstRv.Type(), Field: fields.Items}
+	}
+
+	m.pushField(fields.Items)
+	err := m.mapField(items, structField{value: srcRv})
+	m.popPath()
+	if err != nil {
+		return err
+	}
+
+	for name, value := range map[string]int{
+		fields.Total:   page.Total,
+		fields.Page:    page.Page,
+		fields.PerPage: page.PerPage,
+		fields.Pages:   page.pages(),
+	} {
+		field, ok := dstFields.get(name)
+		if name == "" || !ok {
+			continue
+		}
+		m.pushField(name)
+		err := m.mapValue(field.value, reflect.ValueOf(value))
+		m.popPath()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MapInto transfers values from src to the field of dst at path,
+// i.e. Data or Data.Items. Nil pointers on the way are allocated.
+func (m *Mapper) MapInto(dst interface{}, path string, src interface{}, opts ...Option) error {
+	dstRv, srcRv := reflectValueRemovePtr(dst), reflectValueRemovePtr(src)
+	mp := m.newMapping(opts...)
+	return mp.track(dstRv, srcRv, func() error {
+		field, err := allocStructFieldPath(dstRv, path)
+		if err != nil {
+			return err
+		}
+		for _, name := range strings.Split(path, ".") {
+			mp.pushField(name)
+		}
+		return mp.mapField(field, structField{value: srcRv})
+	})
+}
+
+// MapInto transfers values from src to the field of dst at path
+func MapInto(dst interface{}, path string, src interface{}, opts ...Option) error {
+	m := Mapper{}
+	return m.MapInto(dst, path, src, opts...)
+}
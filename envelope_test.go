@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Map a page of items into an envelope
+func TestMapPage(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	var out struct {
+		Items   []ProductDto `dto:"sort=Name"`
+		Total   int64
+		Page    int
+		PerPage int
+		Pages   int
+	}
+
+	m := Mapper{}
+	err := m.MapPage(&out, commonProducts[:2], PageInfo{Page: 2, PerPage: 2, Total: 5})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []ProductDto{{"Shirt"}, {"Shoes"}}, out.Items)
+	assert.Equal(t, int64(5), out.Total)
+	assert.Equal(t, 2, out.Page)
+	assert.Equal(t, 2, out.PerPage)
+	assert.Equal(t, 3, out.Pages)
+}
+
+// Failed items leave no path behind
+func TestMapPageError(t *testing.T) {
+	var out struct {
+		Items []int
+		Total int
+	}
+	mapper := Mapper{}
+	mp := mapper.newMapping()
+	err := mp.mapPage(reflectValueRemovePtr(&out), reflectValueRemovePtr(commonProducts[:1]), PageInfo{Total: 1})
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+	assert.Empty(t, mp.path)
+}
+
+// Map a page into an envelope with configured field names
+func TestMapPageFields(t *testing.T) {
+	var out struct {
+		Data  []Product
+		Count int
+	}
+
+	m := NewMapper(WithPageFields(PageFields{Items: "Data", Total: "Count"}))
+	err := m.MapPage(&out, commonProducts, PageInfo{Page: 1, PerPage: 10, Total: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, commonProducts, out.Data)
+	assert.Equal(t, 4, out.Count)
+
+	err = (&Mapper{}).MapPage(&out, commonProducts, PageInfo{})
+	assert.ErrorAs(t, err, &FieldNotFoundError{})
+}
+
+// Map into fields of response envelopes
+func TestMapInto(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	var out struct {
+		Data []ProductDto
+		Meta *struct {
+			Best ProductDto
+		}
+		Errors []string
+	}
+
+	err := MapInto(&out, "Data", commonProducts)
+	assert.Nil(t, err)
+	assert.Equal(t, len(commonProducts), len(out.Data))
+	assert.Equal(t, commonProducts[0].Name, out.Data[0].Name)
+
+	err = MapInto(&out, "Meta.Best", &commonProducts[1])
+	assert.Nil(t, err)
+	assert.Equal(t, commonProducts[1].Name, out.Meta.Best.Name)
+
+	err = MapInto(&out, "Meta.Worst", commonProducts[2])
+	assert.ErrorAs(t, err, &FieldNotFoundError{})
+}
@@ -0,0 +1,43 @@
+package dto
+
+// ErrorTranslator converts an error of a Map call.
+// The path is the destination path of the failed value, like Products[2].Price.
+type ErrorTranslator func(path string, err error) error
+
+// SetErrorTranslator sets a function that converts all errors returned from Map calls,
+// for example into application specific error types. Passing nil removes it.
+//
+// Constraint violations are passed as ValidationErrors with an empty path,
+// as each of them contains its own path.
+func (m *Mapper) SetErrorTranslator(fn ErrorTranslator) {
+	m.updateRegistry(func(r *registry) {
+		r.errorTranslator = fn
+	})
+}
+
+// Remember the path of the innermost failed value
+func (m *mapping) recordErrorPath() {
+	if !m.hasErrorPath {
+		m.errorPath = m.pathString()
+		m.hasErrorPath = true
+	}
+}
+
+// Forget the recorded path, if the error was dropped
+func (m *mapping) clearErrorPath() {
+	m.hasErrorPath = false
+}
+
+// Run a top level mapping function, report collected constraint violations
+// and translate the resulting error
+func (m *mapping) run(fn func() error) error {
+	err := fn()
+	path := m.errorPath
+	if err == nil && len(m.violations) > 0 {
+		err, path = m.violations, ""
+	}
+	if err != nil && m.errorTranslator != nil {
+		err = m.errorTranslator(path, err)
+	}
+	return err
+}
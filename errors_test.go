@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldProblem struct {
+	Field  string
+	Detail string
+}
+
+func (fp fieldProblem) Error() string {
+	return fmt.Sprintf("%v: %v", fp.Field, fp.Detail)
+}
+
+// Errors are translated with the path of the failed value
+func TestErrorTranslator(t *testing.T) {
+	m := Mapper{}
+	m.AddConvFunc(func(p Product) (ProductRef, error) {
+		if p.Price == 0 {
+			return ProductRef{}, errors.New("missing price")
+		}
+		return ProductRef{Product: p}, nil
+	})
+	m.SetErrorTranslator(func(path string, err error) error {
+		if ce := (ConversionError{}); errors.As(err, &ce) {
+			err = ce.Err
+		}
+		return fieldProblem{Field: path, Detail: err.Error()}
+	})
+
+	from := ShoppingCart{Products: []Product{{Name: "A", Price: 1}, {Name: "B"}}}
+	var out struct{ Products []ProductRef }
+	err := m.Map(&out, from)
+	assert.Equal(t, fieldProblem{Field: "Products[1]", Detail: "missing price"}, err)
+
+	from.Products[1].Price = 1
+	err = m.Map(&out, from)
+	assert.Nil(t, err)
+
+	m.SetErrorTranslator(nil)
+	from.Products[0].Price = 0
+	err = m.Map(&out, from)
+	assert.EqualError(t, err, "Failed to convert dto.Product to dto.ProductRef at Products[0]: missing price")
+}
+
+// Constraint violations are translated as a whole
+func TestErrorTranslatorViolations(t *testing.T) {
+	m := Mapper{}
+	var translatedPath *string
+	m.SetErrorTranslator(func(path string, err error) error {
+		translatedPath = &path
+		return err
+	})
+
+	var out struct {
+		Count int `dto:"max=1"`
+		Name  string
+	}
+	err := m.Map(&out, struct{ Count int }{2})
+	assert.IsType(t, ValidationErrors{}, err)
+	assert.Equal(t, "", *translatedPath)
+}
+
+// Errors of conversion functions contain the path and types
+func TestConversionError(t *testing.T) {
+	type UserDto struct {
+		ID       [16]byte
+		ParentID [16]byte
+	}
+	errInvalid := errors.New("invalid uuid")
+	m := Mapper{}
+	m.AddConvFunc(func(s string) ([16]byte, error) {
+		var id [16]byte
+		if len(s) != 16 {
+			return id, errInvalid
+		}
+		copy(id[:], s)
+		return id, nil
+	})
+
+	var out UserDto
+	err := m.Map(&out, struct{ ID, ParentID string }{"0123456789abcdef", "x"})
+	assert.Equal(t, ConversionError{
+		Path:     "ParentID",
+		ToType:   reflect.TypeOf([16]byte{}),
+		FromType: reflect.TypeOf(""),
+		Err:      errInvalid,
+	}, err)
+	assert.ErrorIs(t, err, errInvalid)
+}
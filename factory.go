@@ -0,0 +1,57 @@
+package dto
+
+import "reflect"
+
+// ConvFactory creates conversion functions for families of types, like all instantiations
+// of a generic type. It returns nil if it doesn't apply to a type pair.
+// Created functions return values assignable or convertible to the destination type.
+type ConvFactory func(dstType, srcType reflect.Type) func(src reflect.Value) (reflect.Value, error)
+
+// AddConvFactory adds a factory of conversion functions, for example to convert
+// any Optional[T] to *T without registering a function per instantiation.
+// Factories are asked once per type pair in order of registration, conversion functions
+// for exact types take precedence.
+func (m *Mapper) AddConvFactory(factory ConvFactory) {
+	m.updateRegistry(func(r *registry) {
+		r.convFactories = append(r.convFactories, factory)
+	})
+}
+
+// Find a conversion function created by a factory, cached by type pair
+func (r *registry) findFactoryFunc(dstType, srcType reflect.Type) (convertFuncClosure, bool) {
+	if len(r.convFactories) == 0 {
+		return nil, false
+	}
+	pair := typePair{dst: dstType, src: srcType}
+	if closure, ok := r.factoryFuncs.Load(pair); ok {
+		return closure.(convertFuncClosure), closure.(convertFuncClosure) != nil
+	}
+
+	var closure convertFuncClosure
+	for _, factory := range r.convFactories {
+		if fn := factory(dstType, srcType); fn != nil {
+			closure = factoryFuncClosure(dstType, srcType, fn)
+			break
+		}
+	}
+	r.factoryFuncs.Store(pair, closure)
+	return closure, closure != nil
+}
+
+// Make a closure for a function created by a factory
+func factoryFuncClosure(dstType, srcType reflect.Type, fn func(reflect.Value) (reflect.Value, error)) convertFuncClosure {
+	return func(from reflect.Value, m *mapping) (reflect.Value, error) {
+		out, err := fn(from)
+		switch {
+		case err != nil:
+			return reflect.Value{}, err
+		case !out.IsValid():
+			return reflect.Zero(dstType), nil
+		case out.Type().AssignableTo(dstType):
+			return out, nil
+		case out.Type().ConvertibleTo(dstType):
+			return out.Convert(dstType), nil
+		}
+		return reflect.Value{}, NoValidMappingError{ToType: dstType, FromType: srcType}
+	}
+}
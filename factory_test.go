@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testOptional[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Optional[T] to *T for any T
+func testOptionalFactory(dstType, srcType reflect.Type) func(reflect.Value) (reflect.Value, error) {
+	if !SameGeneric(srcType, testOptional[int]{}) || dstType.Kind() != reflect.Ptr ||
+		dstType.Elem() != srcType.Field(0).Type {
+		return nil
+	}
+	return func(src reflect.Value) (reflect.Value, error) {
+		if !src.Field(1).Bool() {
+			return reflect.Value{}, nil
+		}
+		out := reflect.New(dstType.Elem())
+		out.Elem().Set(src.Field(0))
+		return out, nil
+	}
+}
+
+// Conversion factories apply to families of types
+func TestConvFactory(t *testing.T) {
+	type UserDto struct {
+		Name *string
+		Age  *int
+		Tags *[]string
+	}
+	type User struct {
+		Name testOptional[string]
+		Age  testOptional[int]
+		Tags testOptional[[]string]
+	}
+
+	mapper := Mapper{}
+	mapper.AddConvFactory(testOptionalFactory)
+
+	var out UserDto
+	err := mapper.Map(&out, User{
+		Name: testOptional[string]{"Ann", true},
+		Age:  testOptional[int]{},
+		Tags: testOptional[[]string]{[]string{"a"}, true},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Ann", *out.Name)
+	assert.Nil(t, out.Age)
+	assert.Equal(t, []string{"a"}, *out.Tags)
+
+	// exact functions take precedence
+	mapper.AddConvFunc(func(o testOptional[string]) *string {
+		name := "exact"
+		return &name
+	})
+	err = mapper.Map(&out, User{Name: testOptional[string]{"Ann", true}})
+	assert.Nil(t, err)
+	assert.Equal(t, "exact", *out.Name)
+
+	// errors are wrapped
+	mapper.AddConvFactory(func(dstType, srcType reflect.Type) func(reflect.Value) (reflect.Value, error) {
+		return func(src reflect.Value) (reflect.Value, error) {
+			return reflect.Value{}, errors.New("failed")
+		}
+	})
+	err = mapper.Map(&out, User{})
+	assert.ErrorAs(t, err, &ConversionError{})
+}
+
+// Instantiations of the same generic type are recognized
+func TestSameGeneric(t *testing.T) {
+	assert.True(t, SameGeneric(reflect.TypeOf(testOptional[string]{}), testOptional[int]{}))
+	assert.False(t, SameGeneric(reflect.TypeOf(testPage[string]{}), testOptional[int]{}))
+	assert.False(t, SameGeneric(reflect.TypeOf(Product{}), Product{}))
+	assert.False(t, SameGeneric(reflect.TypeOf(&testOptional[int]{}), testOptional[int]{}))
+}
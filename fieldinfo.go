@@ -0,0 +1,38 @@
+package dto
+
+import "reflect"
+
+// FieldInfo describes the destination struct field that is being mapped.
+// Functions can take it as an additional argument to behave differently per field.
+type FieldInfo struct {
+	// Name of the innermost destination struct field, empty outside of structs
+	Name string
+	// Path of the destination value, like Products[2].Price
+	Path string
+	// Tag of the innermost destination struct field
+	Tag reflect.StructTag
+	// Skipped is true if the inspected value was left untouched because its source was skipped,
+	// like a nil pointer with SkipNil. Skipped values are inspected only with WithInspectSkipped.
+	Skipped bool
+}
+
+var fieldInfoRfType = reflect.TypeOf(FieldInfo{})
+
+// Describe the current destination field
+func (m *mapping) fieldInfo() FieldInfo {
+	return FieldInfo{Name: m.field.name, Path: m.pathString(), Tag: m.field.rawTag, Skipped: m.skipped}
+}
+
+// Make arguments of the given injected types for a custom function
+func (m *mapping) injectArgs(types []reflect.Type) []reflect.Value {
+	args := make([]reflect.Value, len(types))
+	for i, argType := range types {
+		switch argType {
+		case mapperPtrRfType:
+			args[i] = reflect.ValueOf(m.Mapper)
+		case fieldInfoRfType:
+			args[i] = reflect.ValueOf(m.fieldInfo())
+		}
+	}
+	return args
+}
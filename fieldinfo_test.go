@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Conversion functions with field info
+func TestConvFuncFieldInfo(t *testing.T) {
+	type EventDto struct {
+		Date  string `layout:"2006-01-02"`
+		Time  string `layout:"15:04"`
+		Stamp string
+	}
+	type Event struct {
+		Date, Time, Stamp time.Time
+	}
+
+	var paths []string
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(t time.Time, mapper *Mapper, field FieldInfo) string {
+		paths = append(paths, field.Path)
+		if layout, ok := field.Tag.Lookup("layout"); ok {
+			return t.Format(layout)
+		}
+		return field.Name
+	})
+
+	ts := time.Date(2022, 3, 4, 10, 30, 0, 0, time.UTC)
+	var out EventDto
+	err := mapper.Map(&out, Event{ts, ts, ts})
+	assert.Nil(t, err)
+	assert.Equal(t, EventDto{Date: "2022-03-04", Time: "10:30", Stamp: "Stamp"}, out)
+	assert.ElementsMatch(t, []string{"Date", "Time", "Stamp"}, paths)
+
+	assert.Panics(t, func() {
+		mapper.AddConvFunc(func(t time.Time, s string) string { return s })
+	})
+}
+
+// Inspection functions with field info
+func TestInspectFuncFieldInfo(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	type CartDto struct {
+		Products []ProductDto
+		Featured ProductDto `audit:"featured"`
+	}
+	type Cart struct {
+		Products []Product
+		Featured Product
+	}
+
+	var paths, tags []string
+	mapper := Mapper{}
+	mapper.AddInspectFunc(func(dto *ProductDto, product Product, field FieldInfo) {
+		paths = append(paths, field.Path)
+	})
+	mapper.AddInspectFunc(func(dto *ProductDto, field FieldInfo, mapper *Mapper) {
+		tags = append(tags, field.Tag.Get("audit"))
+	})
+
+	var out CartDto
+	err := mapper.Map(&out, Cart{Products: commonProducts[:2], Featured: commonProducts[2]})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"Products[0]", "Products[1]", "Featured"}, paths)
+	assert.ElementsMatch(t, []string{"", "", "featured"}, tags)
+
+	assert.Panics(t, func() {
+		mapper.AddInspectFunc(func(dto *ProductDto, product Product, s string) {})
+	})
+}
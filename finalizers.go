@@ -0,0 +1,46 @@
+package dto
+
+import "reflect"
+
+// Finalizer of a root destination type
+type finalizer struct {
+	toType   reflect.Type
+	fromType reflect.Type
+	fun      inspectFuncClosure
+}
+
+// AddFinalizer adds a function that runs on the root destination value after a Map call
+// mapped it completely and without errors, like for computing ETags, signatures or totals
+// over the finished DTO. Unlike inspection functions, finalizers don't run for nested values.
+// Finalizers take the same arguments as inspection functions and run in order of registration.
+//
+//	mapper.AddFinalizer(func(order *OrderDto) error {...})
+//
+// Panics if f is not a valid inspection function
+func (m *Mapper) AddFinalizer(f interface{}) {
+	inType, fromType, closure := makeInspectFuncClosure(f)
+	m.updateRegistry(func(r *registry) {
+		r.finalizers = append(r.finalizers, finalizer{toType: inType, fromType: fromType, fun: closure})
+	})
+}
+
+// Wrap a top level mapping function, so that finalizers of the root run once it succeeded
+func (m *mapping) withFinalizers(dstRv, srcRv reflect.Value, fn func() error) func() error {
+	if len(m.finalizers) == 0 || m.opts.skips(FinalizeStage) {
+		return fn
+	}
+	return func() error {
+		if err := fn(); err != nil || len(m.violations) > 0 {
+			return err
+		}
+		for _, f := range m.finalizers {
+			if f.toType != dstRv.Type() || (f.fromType != nilRecvRfType && f.fromType != srcRv.Type()) {
+				continue
+			}
+			if err := f.fun(dstRv.Addr(), srcRv, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
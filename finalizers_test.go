@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type finalItem struct {
+	Price int
+}
+
+type finalOrder struct {
+	Items []finalItem
+	Sub   *finalOrder
+}
+
+type finalItemDto struct {
+	Price int
+}
+
+type finalOrderDto struct {
+	Items []finalItemDto
+	Sub   *finalOrderDto
+	Total int
+	ETag  string
+}
+
+// Finalizers run in order on the completely mapped root
+func TestFinalizers(t *testing.T) {
+	mapper := Mapper{}
+	mapper.AddInspectFunc(func(item *finalItemDto) {
+		item.Price *= 2
+	})
+	mapper.AddFinalizer(func(order *finalOrderDto) {
+		for _, item := range order.Items {
+			order.Total += item.Price
+		}
+	})
+	mapper.AddFinalizer(func(order *finalOrderDto, src finalOrder) {
+		order.ETag = fmt.Sprintf("%v-%v", order.Total, len(src.Items))
+	})
+
+	order := finalOrderDto{}
+	err := mapper.Map(&order, finalOrder{
+		Items: []finalItem{{Price: 1}, {Price: 2}},
+		Sub:   &finalOrder{Items: []finalItem{{Price: 3}}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 6, order.Total)
+	assert.Equal(t, "6-2", order.ETag)
+
+	// nested values are not finalized
+	assert.Equal(t, 0, order.Sub.Total)
+	assert.Equal(t, "", order.Sub.ETag)
+
+	// other sources don't run finalizers that take a source
+	order = finalOrderDto{}
+	err = mapper.Map(&order, finalOrderDto{Items: []finalItemDto{{Price: 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, order.Total)
+	assert.Equal(t, "", order.ETag)
+}
+
+// Finalizers don't run after errors and their errors are returned
+func TestFinalizerErrors(t *testing.T) {
+	failed := errors.New("failed")
+	calls := 0
+	mapper := Mapper{}
+	mapper.AddFinalizer(func(order *finalOrderDto) error {
+		calls++
+		return failed
+	})
+
+	err := mapper.Map(&finalOrderDto{}, finalOrder{})
+	assert.Equal(t, failed, err)
+	assert.Equal(t, 1, calls)
+
+	err = mapper.Map(&finalOrderDto{}, 42)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Named bit of a flag table
+type namedFlag struct {
+	name string
+	bit  uint64
+}
+
+// AddFlagTable adds a named table of bit flags for the flags and flag tags,
+// like for exposing permission bitmasks. Integer source fields are expanded into
+// []string destination fields with the names of all set flags ordered by their bits,
+// or into bool destination fields that report a single flag.
+//
+//	mapper.AddFlagTable("perms", map[string]uint64{"read": 1, "write": 2, "admin": 4})
+//
+//	type UserDto struct {
+//		Permissions []string `dto:"flags=perms"`
+//		CanWrite    bool     `dto:"from=Permissions,flag=perms.write"`
+//	}
+//
+// Overwrites previous tables with the same name
+func (m *Mapper) AddFlagTable(name string, flags map[string]uint64) {
+	table := make([]namedFlag, 0, len(flags))
+	for flagName, bit := range flags {
+		table = append(table, namedFlag{name: flagName, bit: bit})
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].bit != table[j].bit {
+			return table[i].bit < table[j].bit
+		}
+		return table[i].name < table[j].name
+	})
+
+	m.updateRegistry(func(r *registry) {
+		if len(r.flagTables) == 0 {
+			r.flagTables = make(map[string][]namedFlag)
+		}
+		r.flagTables[name] = table
+	})
+}
+
+// Split the value of a flag tag into table and flag name
+func splitFlagTag(value string) (string, string) {
+	if i := strings.LastIndexByte(value, '.'); i >= 0 {
+		return value[:i], value[i+1:]
+	}
+	return value, ""
+}
+
+// Find the flag table of a flags or flag tag and the bit of a flag tag
+func (r *registry) findFlags(dstType reflect.Type, tags fieldTags) ([]namedFlag, uint64, error) {
+	tag, tableName, flagName := "flags="+tags.flags, tags.flags, ""
+	if tags.flag != "" {
+		tag = "flag=" + tags.flag
+		tableName, flagName = splitFlagTag(tags.flag)
+	}
+	table, ok := r.flagTables[tableName]
+	if !ok {
+		return nil, 0, TagError{Tag: tag, Type: dstType, Reason: "no flag table registered"}
+	}
+	if tags.flag == "" {
+		return table, 0, nil
+	}
+	for _, flag := range table {
+		if flag.name == flagName {
+			return table, flag.bit, nil
+		}
+	}
+	return nil, 0, TagError{Tag: tag, Type: dstType, Reason: "no such flag"}
+}
+
+// Check if the types of a field with the flags or flag tag are valid
+func checkFlagTypes(dstType, srcType reflect.Type, tags fieldTags) error {
+	tag, kind := "flags="+tags.flags, reflect.Slice
+	if tags.flag != "" {
+		tag, kind = "flag="+tags.flag, reflect.Bool
+	}
+	if dstKind := derefType(dstType).Kind(); dstKind != kind {
+		return TagError{Tag: tag, Type: dstType, Reason: "not a " + kind.String()}
+	}
+	if srcType != nil && !isIntegerKind(derefType(srcType).Kind()) {
+		return TagError{Tag: tag, Type: srcType, Reason: "not an integer"}
+	}
+	return nil
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uintptr
+}
+
+// Map an integer bitmask to flag names or a single flag
+func (m *mapping) mapFlags(dstRv, srcRv reflect.Value, tags fieldTags) error {
+	if err := checkFlagTypes(dstRv.Type(), srcRv.Type(), tags); err != nil {
+		return err
+	}
+	table, bit, err := m.findFlags(dstRv.Type(), tags)
+	if err != nil {
+		return err
+	}
+	var mask uint64
+	for srcRv.Kind() == reflect.Ptr && !srcRv.IsNil() {
+		srcRv = srcRv.Elem()
+	}
+	switch srcRv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		mask = uint64(srcRv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		mask = srcRv.Uint()
+	}
+
+	if tags.flag != "" {
+		return m.mapValue(dstRv, reflect.ValueOf(bit != 0 && mask&bit == bit))
+	}
+	names := []string{}
+	for _, flag := range table {
+		if flag.bit != 0 && mask&flag.bit == flag.bit {
+			names = append(names, flag.name)
+		}
+	}
+	return m.mapValue(dstRv, reflect.ValueOf(names))
+}
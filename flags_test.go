@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPermissions uint8
+
+// Bitmasks are expanded into flag names and single flags
+func TestFlagTables(t *testing.T) {
+	type User struct {
+		Name        string
+		Permissions testPermissions
+	}
+	type Role string
+	type UserDto struct {
+		Name        string
+		Permissions []Role `dto:"flags=perms"`
+		CanWrite    bool   `dto:"from=Permissions,flag=perms.write"`
+		IsAdmin     *bool  `dto:"from=Permissions,flag=perms.admin"`
+	}
+	mapper := Mapper{}
+	mapper.AddFlagTable("perms", map[string]uint64{"read": 1, "write": 2, "admin": 4})
+
+	dto := UserDto{}
+	err := mapper.Map(&dto, User{Name: "Bob", Permissions: 3})
+	assert.Nil(t, err)
+	isAdmin := false
+	assert.Equal(t, UserDto{Name: "Bob", Permissions: []Role{"read", "write"}, CanWrite: true, IsAdmin: &isAdmin}, dto)
+
+	err = mapper.Map(&dto, User{})
+	assert.Nil(t, err)
+	assert.Equal(t, []Role{}, dto.Permissions)
+	assert.False(t, dto.CanWrite)
+
+	// tagged fields are covered and valid
+	missing, err := mapper.CheckCoverage(UserDto{}, User{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+	mapper.RegisterPair(UserDto{}, User{})
+	assert.Nil(t, mapper.Validate())
+}
+
+// Missing tables and flags and invalid types are reported
+func TestFlagTableErrors(t *testing.T) {
+	type User struct {
+		Permissions int
+		Name        string
+	}
+	type NoTable struct {
+		Permissions []string `dto:"flags=roles"`
+	}
+	type NoFlag struct {
+		Permissions bool `dto:"flag=perms.delete"`
+	}
+	type NotInteger struct {
+		Name []string `dto:"flags=perms"`
+	}
+	type NotBool struct {
+		Permissions string `dto:"flag=perms.read"`
+	}
+	mapper := Mapper{}
+	mapper.AddFlagTable("perms", map[string]uint64{"read": 1})
+
+	for _, dst := range []interface{}{&NoTable{}, &NoFlag{}, &NotInteger{}, &NotBool{}} {
+		err := mapper.Map(dst, User{Permissions: 1, Name: "Bob"})
+		var te TagError
+		assert.True(t, errors.As(err, &te))
+		mapper.RegisterPair(dst, User{})
+	}
+	var ce ConfigError
+	assert.True(t, errors.As(mapper.Validate(), &ce))
+	assert.Len(t, ce, 4)
+}
@@ -0,0 +1,187 @@
+package dto
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DtoField describes a field of a generated DTO struct
+type DtoField struct {
+	Name string
+	// Type is a type expression, like time.Time or []string
+	Type string
+	// Imports are the paths of packages referenced by Type
+	Imports []string
+}
+
+// GenerateRules control the generation of DTO declarations
+type GenerateRules struct {
+	// Name of the DTO type, the source type name with a Dto suffix by default
+	Name string
+	// Package of the generated file. If set, a package clause and imports
+	// are emitted, otherwise only the type declaration.
+	Package string
+	// Ignore lists source fields left out of the DTO
+	Ignore []string
+	// Redact lists source fields that are declared, but never mapped
+	// because of an ignore tag, like secrets that are masked by hand
+	Redact []string
+	// JSONTags adds json tags with snake_case names
+	JSONTags bool
+}
+
+// GenerateDto emits the formatted declaration of a DTO struct for the source struct
+// srcName with the given fields. Field names are kept, so the DTO is mapped
+// from the source without tags.
+//
+// Fails if a rule references a field that doesn't exist
+func GenerateDto(srcName string, fields []DtoField, rules GenerateRules) ([]byte, error) {
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field.Name] = true
+	}
+	ignored, redacted := make(map[string]bool), make(map[string]bool)
+	for _, list := range []struct {
+		names []string
+		set   map[string]bool
+	}{{rules.Ignore, ignored}, {rules.Redact, redacted}} {
+		for _, name := range list.names {
+			if !names[name] {
+				return nil, fmt.Errorf("Field %v not found in %v", name, srcName)
+			}
+			list.set[name] = true
+		}
+	}
+
+	name := rules.Name
+	if name == "" {
+		name = srcName + "Dto"
+	}
+	imports := make(map[string]bool)
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %v is a DTO of %v\ntype %v struct {\n", name, srcName, name)
+	for _, field := range fields {
+		if ignored[field.Name] {
+			continue
+		}
+		var tags []string
+		if rules.JSONTags {
+			tags = append(tags, fmt.Sprintf(`json:"%v"`, SnakeCase(field.Name)))
+		}
+		if redacted[field.Name] {
+			tags = append(tags, `dto:"ignore"`)
+		}
+		fmt.Fprintf(&body, "%v %v", field.Name, field.Type)
+		if len(tags) > 0 {
+			fmt.Fprintf(&body, " `%v`", strings.Join(tags, " "))
+		}
+		body.WriteByte('\n')
+		for _, path := range field.Imports {
+			imports[path] = true
+		}
+	}
+	body.WriteString("}\n")
+
+	if rules.Package == "" {
+		return format.Source(body.Bytes())
+	}
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "package %v\n\n", rules.Package)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		file.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&file, "%q\n", path)
+		}
+		file.WriteString(")\n\n")
+	}
+	file.Write(body.Bytes())
+	return format.Source(file.Bytes())
+}
+
+// GenerateDtoFor emits the declaration of a DTO struct for the type of src,
+// which has to be a named struct or a pointer to one. Unexported and ignored fields,
+// as well as functions and channels, are left out. Embedded structs are flattened.
+// If rules.Package is the package of src, its types are not qualified.
+//
+// Fails if src is not a named struct or a rule references a field that doesn't exist
+func GenerateDtoFor(src interface{}, rules GenerateRules) ([]byte, error) {
+	srcType := reflect.TypeOf(src)
+	for srcType.Kind() == reflect.Ptr {
+		srcType = srcType.Elem()
+	}
+	if srcType.Kind() != reflect.Struct || srcType.Name() == "" {
+		return nil, fmt.Errorf("Type %v is not a named struct", typeName(srcType))
+	}
+
+	// name of the source package, as in qualified type names
+	srcPkg := srcType.String()[:strings.IndexByte(srcType.String(), '.')+1]
+	var fields []DtoField
+	for _, info := range structFieldInfos(srcType) {
+		fieldType := srcType.FieldByIndex(info.index).Type
+		if !info.exported || isOpaqueKind(fieldType.Kind()) {
+			continue
+		}
+		field := DtoField{Name: info.name, Type: typeName(fieldType)}
+		for _, pkgPath := range typePackages(fieldType, nil) {
+			if pkgPath == srcType.PkgPath() && srcPkg == rules.Package+"." {
+				field.Type = unqualify(field.Type, srcPkg)
+				continue
+			}
+			field.Imports = append(field.Imports, pkgPath)
+		}
+		fields = append(fields, field)
+	}
+
+	srcName := srcType.Name()
+	if i := strings.IndexByte(srcName, '['); i >= 0 {
+		srcName = srcName[:i]
+	}
+	return GenerateDto(srcName, fields, rules)
+}
+
+// Collect the package paths of named types a type is composed of
+func typePackages(rfType reflect.Type, paths []string) []string {
+	if rfType.PkgPath() != "" {
+		return append(paths, rfType.PkgPath())
+	}
+	switch rfType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typePackages(rfType.Elem(), paths)
+	case reflect.Map:
+		return typePackages(rfType.Elem(), typePackages(rfType.Key(), paths))
+	}
+	return paths
+}
+
+// Remove a package qualifier from a type expression, like main. from []main.User
+func unqualify(expr, qualifier string) string {
+	return regexp.MustCompile(`\b`+regexp.QuoteMeta(qualifier)).ReplaceAllString(expr, "")
+}
+
+// SnakeCase converts a field name to snake case, like UserID to user_id
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
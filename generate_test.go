@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// DTO declarations are generated from source structs
+func TestGenerateDtoFor(t *testing.T) {
+	type Audit struct {
+		CreatedAt time.Time
+	}
+	type User struct {
+		Audit
+		ID           int64
+		Name         string
+		Email        string
+		PasswordHash []byte
+		Tags         map[string]*Product
+		OnChange     func()
+		Internal     string `dto:"ignore"`
+		secret       string
+	}
+
+	source, err := GenerateDtoFor(&User{}, GenerateRules{
+		Package:  "dto",
+		Ignore:   []string{"PasswordHash"},
+		Redact:   []string{"Email"},
+		JSONTags: true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `package dto
+
+import (
+	"time"
+)
+
+// UserDto is a DTO of User
+type UserDto struct {
+	CreatedAt time.Time           `+"`"+`json:"created_at"`+"`"+`
+	ID        int64               `+"`"+`json:"id"`+"`"+`
+	Name      string              `+"`"+`json:"name"`+"`"+`
+	Email     string              `+"`"+`json:"email" dto:"ignore"`+"`"+`
+	Tags      map[string]*Product `+"`"+`json:"tags"`+"`"+`
+}
+`, string(source))
+
+	_, err = GenerateDtoFor(User{}, GenerateRules{Ignore: []string{"Password"}})
+	assert.EqualError(t, err, "Field Password not found in User")
+}
+
+// Field names are converted to snake case for json tags
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "first_name", SnakeCase("FirstName"))
+	assert.Equal(t, "user_id", SnakeCase("UserID"))
+	assert.Equal(t, "http_server", SnakeCase("HTTPServer"))
+	assert.Equal(t, "address2", SnakeCase("Address2"))
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Package paths in type names of generic instantiations, like github.com/user/repo.Product
+var typeArgPathRe = regexp.MustCompile(`(?:[\w.\-]+/)+([\w\-]+)\.`)
+
+// Get a readable name of a type. Type arguments of generic instantiations
+// contain full package paths, which are shortened to their last element,
+// so Page[github.com/user/repo.Product] becomes Page[repo.Product].
+func typeName(rfType reflect.Type) string {
+	if rfType == nil {
+		return "<nil>"
+	}
+	return typeArgPathRe.ReplaceAllString(rfType.String(), "$1.")
+}
+
+// SameGeneric reports whether rfType is an instantiation of the same generic type as sample,
+// like SameGeneric(reflect.TypeOf(Optional[string]{}), Optional[int]{}). Pointers are not removed.
+func SameGeneric(rfType reflect.Type, sample interface{}) bool {
+	sampleType := reflect.TypeOf(sample)
+	if rfType == nil || sampleType == nil || rfType.PkgPath() != sampleType.PkgPath() {
+		return false
+	}
+	name, sampleName := rfType.Name(), sampleType.Name()
+	i, j := strings.IndexByte(name, '['), strings.IndexByte(sampleName, '[')
+	return i > 0 && j > 0 && name[:i] == sampleName[:j]
+}
+
+// AddTypedConvFunc adds a conversion function like AddConvFunc,
+// but its signature is checked at compile time.
+// Useful for functions of generic instantiations, like func(Page[User]) (PageDto, error).
+func AddTypedConvFunc[From, To any](m *Mapper, f func(From) (To, error), opts ...ConvFuncOption) {
+	m.AddConvFunc(f, opts...)
+}
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPage[T any] struct {
+	Items []T
+	Total int
+}
+
+type testResult[T any] struct {
+	Value T
+	Err   string
+}
+
+// Generic instantiations are mapped like other types
+func TestGenericTypes(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+
+	var out testPage[ProductDto]
+	err := Map(&out, testPage[Product]{Items: commonProducts[:2], Total: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, testPage[ProductDto]{Items: []ProductDto{{commonProducts[0].Name}, {commonProducts[1].Name}}, Total: 10}, out)
+
+	// conversion functions for instantiations
+	mapper := Mapper{}
+	AddTypedConvFunc(&mapper, func(r testResult[Product]) (ProductDto, error) {
+		if r.Err != "" {
+			return ProductDto{}, errors.New(r.Err)
+		}
+		return ProductDto{Name: r.Value.Name}, nil
+	})
+	var outResults []ProductDto
+	err = mapper.Map(&outResults, []testResult[Product]{{Value: commonProducts[0]}})
+	assert.Nil(t, err)
+	assert.Equal(t, []ProductDto{{commonProducts[0].Name}}, outResults)
+
+	// other instantiations are not affected
+	var outString ProductDto
+	err = mapper.Map(&outString, testResult[string]{Value: "x"})
+	assert.Nil(t, err)
+
+	err = mapper.Map(&outResults, []testResult[Product]{{Err: "not found"}})
+	assert.EqualError(t, err, "Failed to convert dto.testResult[dto-mapper.Product] to dto.ProductDto at [0]: not found")
+}
+
+// Type names of instantiations are shortened
+func TestTypeName(t *testing.T) {
+	assert.Equal(t, "dto.testPage[dto-mapper.Product]", typeName(reflect.TypeOf(testPage[Product]{})))
+	assert.Equal(t, "map[string]dto.testPage[*dto-mapper.Product]", typeName(reflect.TypeOf(map[string]testPage[*Product]{})))
+	assert.Equal(t, "dto.testPage[int]", typeName(reflect.TypeOf(testPage[int]{})))
+	assert.Equal(t, "<nil>", typeName(nil))
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "reflect"
+
+// InjectKey is the context key type of values injected into fields tagged with inject,
+// for example by middleware: context.WithValue(ctx, dto.InjectKey("requestID"), id)
+type InjectKey string
+
+// WithInjectValues sets values that are injected into destination fields tagged with inject=name.
+// Values are looked up by name, then in the context set by WithContext by InjectKey(name).
+// Usually passed to a single Map call.
+func WithInjectValues(values map[string]interface{}) Option {
+	return func(o *options) {
+		o.injectValues = values
+	}
+}
+
+// Look up an injected value by name
+func (m *mapping) injectedValue(name string) (interface{}, bool) {
+	if value, ok := m.opts.injectValues[name]; ok {
+		return value, true
+	}
+	if m.opts.ctx != nil {
+		if value := m.opts.ctx.Value(InjectKey(name)); value != nil {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// Map an injected value into a field. Fields without a value are left untouched.
+func (m *mapping) mapInjected(dstRv reflect.Value, name string) error {
+	value, ok := m.injectedValue(name)
+	if !ok || value == nil {
+		return nil
+	}
+	return m.mapValue(dstRv, reflect.ValueOf(value))
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Values are injected into tagged fields
+func TestInject(t *testing.T) {
+	type ItemDto struct {
+		Name   string
+		Tenant string `dto:"inject=tenant"`
+	}
+	type ResponseDto struct {
+		RequestID string `dto:"inject=requestID"`
+		TraceID   *int64 `dto:"inject=traceID"`
+		Items     []ItemDto
+	}
+	type Response struct {
+		RequestID string
+		Items     []Product
+	}
+
+	ctx := context.WithValue(context.Background(), InjectKey("traceID"), 42)
+	from := Response{RequestID: "from source", Items: commonProducts[:1]}
+
+	var out ResponseDto
+	err := Map(&out, from, WithContext(ctx), WithInjectValues(map[string]interface{}{
+		"requestID": "req-1",
+		"tenant":    "acme",
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, "req-1", out.RequestID)
+	assert.Equal(t, int64(42), *out.TraceID)
+	assert.Equal(t, []ItemDto{{Name: commonProducts[0].Name, Tenant: "acme"}}, out.Items)
+
+	// fields without values are left untouched
+	out = ResponseDto{RequestID: "kept"}
+	err = Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, "kept", out.RequestID)
+	assert.Nil(t, out.TraceID)
+}
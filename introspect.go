@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// ConverterInfo describes a registered conversion function
+type ConverterInfo struct {
+	From reflect.Type
+	To   reflect.Type
+	// Scope of functions added with AddScopedConvFunc
+	Scope string
+	// Owner struct of functions added with AddConvFuncFor
+	Owner reflect.Type
+	// Underlying is set for functions added with AddConvFuncForUnderlying
+	Underlying bool
+	// Name of the function, like main.parseUUID
+	Name string
+	// Cacheable is set for functions added with the Cacheable or Pure option
+	Cacheable bool
+	// Pure is set for functions added with the Pure option
+	Pure bool
+}
+
+// HookInfo describes a registered inspection function
+type HookInfo struct {
+	To reflect.Type
+	// From is nil if the function doesn't take the source value
+	From reflect.Type
+	// Pattern of functions added with AddInspectFuncAt
+	Pattern string
+	// Name of the function, like main.checkLink
+	Name string
+}
+
+// ShadowedConversion describes a conversion function that was applied to a value
+// which could have been assigned or converted without it
+type ShadowedConversion struct {
+	Path string
+	From reflect.Type
+	To   reflect.Type
+}
+
+// WithShadowHandler sets a function that is called whenever a conversion function
+// takes precedence over direct assignment or conversion, like a function for string
+// that affects all strings. Useful for logging during development.
+func WithShadowHandler(handler func(ShadowedConversion)) Option {
+	return func(o *options) {
+		o.shadowHandler = handler
+	}
+}
+
+// Report a conversion function that shadows direct assignment or conversion
+func (m *mapping) reportShadowed(dstType, srcType reflect.Type) {
+	if m.opts.shadowHandler == nil {
+		return
+	}
+	if srcType.AssignableTo(dstType) || canConvert(dstType, srcType) {
+		m.opts.shadowHandler(ShadowedConversion{Path: m.pathString(), From: srcType, To: dstType})
+	}
+}
+
+// Get the name of a function, like main.parseUUID
+func funcName(f interface{}) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// Get the source type of an inspection function for HookInfo
+func hookSourceType(fromType reflect.Type) reflect.Type {
+	if fromType == nilRecvRfType {
+		return nil
+	}
+	return fromType
+}
+
+// Record a conversion function, replacing a previous one for the same pair
+func (r *registry) addConverterInfo(info ConverterInfo) {
+	for i, prev := range r.converters {
+		if prev.From == info.From && prev.To == info.To && prev.Scope == info.Scope && prev.Owner == info.Owner &&
+			prev.Underlying == info.Underlying {
+			r.converters[i] = info
+			return
+		}
+	}
+	r.converters = append(r.converters, info)
+}
+
+// Converters lists the registered conversion functions in order of registration
+func (m *Mapper) Converters() []ConverterInfo {
+	return append([]ConverterInfo(nil), m.loadRegistry().converters...)
+}
+
+// InspectFuncs lists the registered inspection functions in order of registration
+func (m *Mapper) InspectFuncs() []HookInfo {
+	return append([]HookInfo(nil), m.loadRegistry().hooks...)
+}
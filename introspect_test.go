@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func formatPrice(p float32) string {
+	return ""
+}
+
+func checkProduct(p *Product) {}
+
+// Registered functions are listed with their names
+func TestIntrospection(t *testing.T) {
+	m := Mapper{}
+	m.AddConvFunc(formatPrice)
+	m.AddScopedConvFunc("admin", formatPrice)
+	m.AddConvFunc(formatPrice)
+	m.AddInspectFunc(checkProduct)
+	m.AddInspectFuncAt("Products[*]", func(p *Product, from Product) {})
+
+	converters := m.Converters()
+	floatType, stringType := reflect.TypeOf(float32(0)), reflect.TypeOf("")
+	assert.Equal(t, []ConverterInfo{
+		{From: floatType, To: stringType, Name: "github.com/dranikpg/dto-mapper.formatPrice"},
+		{From: floatType, To: stringType, Scope: "admin", Name: "github.com/dranikpg/dto-mapper.formatPrice"},
+	}, converters)
+
+	hooks := m.InspectFuncs()
+	productType := reflect.TypeOf(Product{})
+	assert.Len(t, hooks, 2)
+	assert.Equal(t, HookInfo{To: productType, Name: "github.com/dranikpg/dto-mapper.checkProduct"}, hooks[0])
+	assert.Equal(t, productType, hooks[1].From)
+	assert.Equal(t, "Products[*]", hooks[1].Pattern)
+	assert.True(t, strings.HasPrefix(hooks[1].Name, "github.com/dranikpg/dto-mapper.TestIntrospection"))
+}
+
+// Conversion functions that take precedence over assignment are reported
+func TestShadowHandler(t *testing.T) {
+	m := Mapper{}
+	m.AddConvFunc(func(p RawPassword) string {
+		return "hash:" + p
+	})
+	m.AddConvFunc(func(p float32) int {
+		return int(p)
+	})
+
+	var shadowed []ShadowedConversion
+	m.Configure(WithShadowHandler(func(sc ShadowedConversion) {
+		shadowed = append(shadowed, sc)
+	}))
+
+	var out struct {
+		Name  string
+		Price int
+	}
+	err := m.Map(&out, commonProducts[0])
+	assert.Nil(t, err)
+	stringType := reflect.TypeOf("")
+	assert.ElementsMatch(t, []ShadowedConversion{
+		{Path: "Name", From: stringType, To: stringType},
+		{Path: "Price", From: reflect.TypeOf(float32(0)), To: reflect.TypeOf(0)},
+	}, shadowed)
+
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// KeyCollisionError indicates that distinct map keys are equal after key functions are applied
+type KeyCollisionError struct {
+	ToType reflect.Type
+	Key    string
+}
+
+func (kce KeyCollisionError) Error() string {
+	return fmt.Sprintf("Multiple keys of %v map to %q", typeName(kce.ToType), kce.Key)
+}
+
+// AddKeyFunc adds a function that canonicalizes string keys when mapping maps,
+// like strings.ToLower, as upstream payloads are often inconsistent about keys.
+// Functions are applied in order of registration to mapped keys of string kinds.
+// Keys that become equal fail mapping with a KeyCollisionError.
+func (m *Mapper) AddKeyFunc(f func(key string) string) {
+	m.updateRegistry(func(r *registry) {
+		r.keyFuncs = append(r.keyFuncs, f)
+	})
+}
+
+// Canonicalize a mapped map key with key functions
+// Returns false if there are none or the key is not a string
+func (m *mapping) canonicalizeKey(key reflect.Value) bool {
+	if len(m.keyFuncs) == 0 || key.Kind() != reflect.String {
+		return false
+	}
+	for _, f := range m.keyFuncs {
+		key.SetString(f(key.String()))
+	}
+	return true
+}
+
+// Check if an assignable value of the given kind has to be copied, by the assign policy
+// or because it might contain map keys to canonicalize
+func (m *mapping) copiesKind(kind reflect.Kind) bool {
+	switch {
+	case len(m.keyFuncs) == 0:
+		return m.opts.copiesKind(kind)
+	case kind == reflect.Map, kind == reflect.Struct, kind == reflect.Array:
+		return true
+	}
+	return m.opts.copiesKind(kind)
+}
+
+// Map a map key. Numbers are formatted to and parsed from strings,
+// unless a conversion function applies.
+func (m *mapping) mapKey(dstRv, srcRv reflect.Value) error {
+	if converted, err := m.runConvFuncs(dstRv, srcRv); converted {
+		return err
+	}
+	if converted, err := convertNumericKey(dstRv, srcRv); converted {
+		return err
+	}
+	return m.mapValue(dstRv, srcRv)
+}
+
+// Check if a kind is an integer or floating point kind
+func isNumericKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// Format a number to a string or parse a string to a number with strconv
+// Returns false if the values are not a number and a string
+func convertNumericKey(dstRv, srcRv reflect.Value) (bool, error) {
+	tk, fk := dstRv.Kind(), srcRv.Kind()
+	switch {
+	case tk == reflect.String && isNumericKind(fk):
+		var s string
+		switch {
+		case fk <= reflect.Int64:
+			s = strconv.FormatInt(srcRv.Int(), 10)
+		case fk <= reflect.Uintptr:
+			s = strconv.FormatUint(srcRv.Uint(), 10)
+		default:
+			s = strconv.FormatFloat(srcRv.Float(), 'g', -1, srcRv.Type().Bits())
+		}
+		dstRv.SetString(s)
+		return true, nil
+	case fk == reflect.String && isNumericKind(tk):
+		s, bits := srcRv.String(), dstRv.Type().Bits()
+		var err error
+		switch {
+		case tk <= reflect.Int64:
+			var v int64
+			v, err = strconv.ParseInt(s, 10, bits)
+			dstRv.SetInt(v)
+		case tk <= reflect.Uintptr:
+			var v uint64
+			v, err = strconv.ParseUint(s, 10, bits)
+			dstRv.SetUint(v)
+		default:
+			var v float64
+			v, err = strconv.ParseFloat(s, bits)
+			dstRv.SetFloat(v)
+		}
+		if err != nil {
+			return true, ParseError{Value: s, Type: dstRv.Type()}
+		}
+		return true, nil
+	}
+	return false, nil
+}
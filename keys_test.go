@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Key functions canonicalize string keys of mapped maps
+func TestAddKeyFunc(t *testing.T) {
+	mapper := NewMapper()
+	mapper.AddKeyFunc(strings.TrimSpace)
+	mapper.AddKeyFunc(strings.ToLower)
+	mapper.AddKeyFunc(func(key string) string {
+		return strings.TrimPrefix(key, "x-")
+	})
+
+	var out struct {
+		Headers map[string]string
+		Counts  map[string]int64
+	}
+	from := struct {
+		Headers map[string]string
+		Counts  map[string]int
+	}{
+		Headers: map[string]string{" X-Request-ID": "1", "Accept ": "json"},
+		Counts:  map[string]int{"Shirts": 2},
+	}
+	err := mapper.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"request-id": "1", "accept": "json"}, out.Headers)
+	assert.Equal(t, map[string]int64{"shirts": 2}, out.Counts)
+	assert.Contains(t, from.Headers, " X-Request-ID")
+
+	var counts map[string]int
+	err = mapper.Map(&counts, map[string]int{"Shirts": 1, "shirts": 2})
+	assert.ErrorAs(t, err, &KeyCollisionError{})
+}
+
+// Numeric keys are formatted to and parsed from string keys
+func TestNumericKeys(t *testing.T) {
+	type UserID uint32
+
+	var names map[string]string
+	err := Map(&names, map[int64]string{1: "Alice", -2: "Bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"1": "Alice", "-2": "Bob"}, names)
+
+	var byID map[UserID]string
+	err = Map(&byID, map[string]string{"1": "Alice", "2": "Bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[UserID]string{1: "Alice", 2: "Bob"}, byID)
+
+	var prices map[string]int
+	err = Map(&prices, map[float64]int{9.5: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"9.5": 1}, prices)
+
+	err = Map(&byID, map[string]string{"first": "Alice"})
+	assert.ErrorAs(t, err, &MapKeyError{})
+	assert.ErrorIs(t, err, ParseError{Value: "first", Type: reflect.TypeOf(UserID(0))})
+
+	mapper := NewMapper()
+	mapper.AddConvFunc(func(id int64) string { return "user-" + strconv.FormatInt(id, 10) })
+	err = mapper.Map(&names, map[int64]string{1: "Alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"user-1": "Alice"}, names)
+}
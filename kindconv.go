@@ -0,0 +1,68 @@
+package dto
+
+import "reflect"
+
+// KindPair is a pair of basic kinds of a destination and a source value
+type KindPair struct {
+	To   reflect.Kind
+	From reflect.Kind
+}
+
+// KindConverter sets dst to the value of src, like dst.SetInt(int64(math.Round(src.Float()))).
+// dst and src are of the kinds it was registered for.
+type KindConverter func(dst, src reflect.Value) error
+
+// KindConverters is a table of default converters by kind pair
+type KindConverters map[KindPair]KindConverter
+
+// SetKindConverters replaces the default conversions between basic kinds with the given table,
+// like to round floats to ints instead of truncating them or to format numbers as strings.
+// Converters apply to values of the given kinds that are neither assignable to each other
+// nor handled by conversion functions, pairs not in the table are converted as usual.
+// Errors are wrapped in a ConversionError.
+//
+//	mapper.SetKindConverters(dto.KindConverters{
+//	    {To: reflect.Int, From: reflect.Float64}: func(dst, src reflect.Value) error {
+//	        dst.SetInt(int64(math.Round(src.Float())))
+//	        return nil
+//	    },
+//	})
+//
+// Panics if a pair is not of basic kinds
+func (m *Mapper) SetKindConverters(table KindConverters) {
+	converters := make(KindConverters, len(table))
+	for pair, conv := range table {
+		if !isBasicKind(pair.To) || !isBasicKind(pair.From) {
+			panic("Kind converters must convert between basic kinds")
+		}
+		converters[pair] = conv
+	}
+	m.updateRegistry(func(r *registry) {
+		r.kindConverters = converters
+	})
+}
+
+// Find the default converter for a kind pair
+func (r *registry) findKindConverter(dstKind, srcKind reflect.Kind) (KindConverter, bool) {
+	conv, ok := r.kindConverters[KindPair{To: dstKind, From: srcKind}]
+	return conv, ok
+}
+
+// Check if there is a default converter for a kind pair
+func (r *registry) hasKindConverter(dstKind, srcKind reflect.Kind) bool {
+	_, ok := r.findKindConverter(dstKind, srcKind)
+	return ok
+}
+
+// Convert values with a default converter of their kinds
+// Returns false if there is none
+func (m *mapping) runKindConverter(dstRv, srcRv reflect.Value) (bool, error) {
+	conv, ok := m.findKindConverter(dstRv.Kind(), srcRv.Kind())
+	if !ok {
+		return false, nil
+	}
+	if err := conv(dstRv, srcRv); err != nil {
+		return true, ConversionError{Path: m.pathString(), ToType: dstRv.Type(), FromType: srcRv.Type(), Err: err}
+	}
+	return true, nil
+}
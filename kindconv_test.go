@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type kindCelsius float64
+
+// Kind converters replace default conversions between basic kinds
+func TestKindConverters(t *testing.T) {
+	mapper := Mapper{}
+	mapper.SetKindConverters(KindConverters{
+		{To: reflect.Int, From: reflect.Float64}: func(dst, src reflect.Value) error {
+			dst.SetInt(int64(math.Round(src.Float())))
+			return nil
+		},
+		{To: reflect.String, From: reflect.Int}: func(dst, src reflect.Value) error {
+			dst.SetString(strconv.FormatInt(src.Int(), 10))
+			return nil
+		},
+	})
+
+	type Reading struct {
+		Temperature kindCelsius
+		Humidity    float64
+		Station     int
+		Ratio       float32
+	}
+	type ReadingDto struct {
+		Temperature int
+		Humidity    *int
+		Station     string
+		Ratio       float64
+	}
+	dto := ReadingDto{}
+	err := mapper.Map(&dto, Reading{Temperature: 21.6, Humidity: 40.5, Station: 42, Ratio: 0.5})
+	assert.Nil(t, err)
+	assert.Equal(t, 22, dto.Temperature)
+	assert.Equal(t, 41, *dto.Humidity)
+	assert.Equal(t, "42", dto.Station)
+	// other pairs are converted as usual
+	assert.Equal(t, 0.5, dto.Ratio)
+
+	// conversion functions take precedence
+	mapper.AddConvFunc(func(c kindCelsius) int {
+		return int(c)
+	})
+	err = mapper.Map(&dto, Reading{Temperature: 21.6})
+	assert.Nil(t, err)
+	assert.Equal(t, 21, dto.Temperature)
+
+	// pairs without conversion are covered
+	var diff FieldDiff
+	diff, err = mapper.DiffFields(ReadingDto{}, Reading{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+
+	// tables are replaced as a whole
+	mapper.SetKindConverters(nil)
+	err = mapper.Map(&dto, Reading{Humidity: 40.5})
+	assert.Nil(t, err)
+	assert.Equal(t, 40, *dto.Humidity)
+}
+
+// Errors of kind converters are wrapped with the path
+func TestKindConverterErrors(t *testing.T) {
+	errNegative := errors.New("negative")
+	mapper := Mapper{}
+	mapper.SetKindConverters(KindConverters{
+		{To: reflect.Uint, From: reflect.Int}: func(dst, src reflect.Value) error {
+			if src.Int() < 0 {
+				return errNegative
+			}
+			dst.SetUint(uint64(src.Int()))
+			return nil
+		},
+	})
+	var dto struct {
+		Count uint
+	}
+	err := mapper.Map(&dto, struct{ Count int }{Count: -1})
+	var ce ConversionError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, "Count", ce.Path)
+	assert.True(t, errors.Is(err, errNegative))
+
+	assert.Panics(t, func() {
+		mapper.SetKindConverters(KindConverters{
+			{To: reflect.Struct, From: reflect.Map}: func(dst, src reflect.Value) error { return nil },
+		})
+	})
+}
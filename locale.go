@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale defines number and date formats of a region
+type Locale struct {
+	// Decimal separator, like "," in 1.234,56
+	Decimal string
+	// Characters used to group digits, like "." in 1.234,56
+	Group string
+	// Date layouts as accepted by time.Parse, tried in order
+	DateLayouts []string
+}
+
+var (
+	// LocaleEnglish parses numbers like 1,234.56 and dates like 01/31/2006
+	LocaleEnglish = Locale{Decimal: ".", Group: ",", DateLayouts: []string{"01/02/2006", "Jan 2, 2006", "2006-01-02"}}
+	// LocaleGerman parses numbers like 1.234,56 and dates like 31.01.2006
+	LocaleGerman = Locale{Decimal: ",", Group: ".", DateLayouts: []string{"02.01.2006", "2.1.2006", "2006-01-02"}}
+	// LocaleFrench parses numbers like 1 234,56 and dates like 31/01/2006
+	LocaleFrench = Locale{Decimal: ",", Group: " \u00a0\u202f", DateLayouts: []string{"02/01/2006", "2006-01-02"}}
+)
+
+var stringRfType = reflect.TypeOf("")
+
+// Name of locale conversion functions in ConverterInfo
+const localeFuncName = "dto.AddLocaleConvFuncs"
+
+var floatRfTypes = []reflect.Type{reflect.TypeOf(float32(0)), reflect.TypeOf(float64(0))}
+
+// WithLocale selects the locale used by conversion functions added with AddLocaleConvFuncs.
+// Usually passed to a single Map call.
+func WithLocale(locale Locale) Option {
+	return func(o *options) {
+		o.locale = &locale
+	}
+}
+
+// Normalize a number by removing group separators and replacing the decimal separator
+func (l Locale) normalizeNumber(s string) string {
+	s = strings.TrimSpace(s)
+	if l.Group != "" {
+		s = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(l.Group, r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if l.Decimal != "" && l.Decimal != "." {
+		s = strings.Replace(s, l.Decimal, ".", 1)
+	}
+	return s
+}
+
+// Parse a number into a value of an integer or float type
+func (l Locale) parseNumber(s string, rfType reflect.Type) (reflect.Value, error) {
+	number := l.normalizeNumber(s)
+	out := reflect.New(rfType).Elem()
+	var err error
+	switch rfType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var v int64
+		if v, err = strconv.ParseInt(number, 10, rfType.Bits()); err == nil {
+			out.SetInt(v)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var v uint64
+		if v, err = strconv.ParseUint(number, 10, rfType.Bits()); err == nil {
+			out.SetUint(v)
+		}
+	case reflect.Float32, reflect.Float64:
+		var v float64
+		if v, err = strconv.ParseFloat(number, rfType.Bits()); err == nil {
+			out.SetFloat(v)
+		}
+	}
+	if err != nil {
+		return out, ParseError{Value: s, Type: rfType}
+	}
+	return out, nil
+}
+
+// Parse a date by the first matching layout
+func (l Locale) parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range l.DateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ParseError{Value: s, Type: timeRfType}
+}
+
+// Get the locale of the Map call or the default
+func (m *mapping) locale(fallback Locale) Locale {
+	if m.opts.locale != nil {
+		return *m.opts.locale
+	}
+	return fallback
+}
+
+// AddLocaleConvFuncs adds conversion functions that parse strings into integers, floats
+// and time.Time by the locale selected with WithLocale, or by the fallback if none is selected.
+//
+// Overwrites previous functions from string to those types
+func (m *Mapper) AddLocaleConvFuncs(fallback Locale) {
+	for _, numType := range append(append([]reflect.Type{}, integerRfTypes...), floatRfTypes...) {
+		numType := numType
+		info := ConverterInfo{From: stringRfType, To: numType, Name: localeFuncName}
+		m.addConvFuncClosure(info, func(from reflect.Value, m *mapping) (reflect.Value, error) {
+			return m.locale(fallback).parseNumber(from.String(), numType)
+		})
+	}
+	info := ConverterInfo{From: stringRfType, To: timeRfType, Name: localeFuncName}
+	m.addConvFuncClosure(info, func(from reflect.Value, m *mapping) (reflect.Value, error) {
+		t, err := m.locale(fallback).parseDate(from.String())
+		return reflect.ValueOf(t), err
+	})
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type feedRow struct {
+	Price   string
+	Stock   string
+	Updated string
+}
+
+type feedItem struct {
+	Price   float64
+	Stock   uint16
+	Updated time.Time
+}
+
+// Numbers and dates are parsed by the fallback locale
+func TestLocaleConvFuncs(t *testing.T) {
+	m := Mapper{}
+	m.AddLocaleConvFuncs(LocaleGerman)
+
+	var out feedItem
+	err := m.Map(&out, feedRow{"1.234,56", "12.000", "31.01.2022"})
+	assert.Nil(t, err)
+	assert.Equal(t, feedItem{1234.56, 12000, time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)}, out)
+
+	err = m.Map(&out, feedRow{"1,5", "70.000", "31.01.2022"})
+	assert.ErrorAs(t, err, &ParseError{})
+}
+
+// The locale of a Map call takes precedence over the fallback
+func TestCallLocale(t *testing.T) {
+	m := Mapper{}
+	m.AddLocaleConvFuncs(LocaleGerman)
+
+	var out feedItem
+	err := m.Map(&out, feedRow{"1,234.56", "1,000", "01/31/2022"}, WithLocale(LocaleEnglish))
+	assert.Nil(t, err)
+	assert.Equal(t, feedItem{1234.56, 1000, time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)}, out)
+
+	err = m.Map(&out, feedRow{"1 234,56", "1 000", "31/01/2022"}, WithLocale(LocaleFrench))
+	assert.Nil(t, err)
+	assert.Equal(t, feedItem{1234.56, 1000, time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)}, out)
+}
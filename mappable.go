@@ -0,0 +1,56 @@
+package dto
+
+import "reflect"
+
+// Mappable is implemented by destination types that map values into themselves.
+// It takes precedence over all mapping steps except conversion functions and decode hooks.
+// m can be used to map nested values. Source pointers are dereferenced first.
+type Mappable interface {
+	MapFrom(src interface{}, m *Mapper) error
+}
+
+// MappableSource is implemented by source types that map themselves into destinations.
+// dst is a pointer to the destination value. Mappable destinations take precedence.
+type MappableSource interface {
+	MapInto(dst interface{}, m *Mapper) error
+}
+
+var (
+	mappableRfType       = reflect.TypeOf((*Mappable)(nil)).Elem()
+	mappableSourceRfType = reflect.TypeOf((*MappableSource)(nil)).Elem()
+)
+
+// Check if a destination type is Mappable from a source type.
+// Values of the same type are not mapped by themselves.
+func isMappableFrom(dstType, srcType reflect.Type) bool {
+	return dstType != srcType && dstType.Kind() != reflect.Ptr && srcType.Kind() != reflect.Ptr &&
+		srcType.Kind() != reflect.Interface && reflect.PtrTo(dstType).Implements(mappableRfType)
+}
+
+// Check if a source type is a MappableSource for a destination type
+func isMappableInto(dstType, srcType reflect.Type) bool {
+	return dstType != srcType && dstType.Kind() != reflect.Ptr && srcType.Kind() != reflect.Ptr &&
+		srcType.Kind() != reflect.Interface && reflect.PtrTo(srcType).Implements(mappableSourceRfType)
+}
+
+// Map values with Mappable or MappableSource implementations
+// Returns false if neither applies
+func (m *mapping) runMappable(dstRv, srcRv reflect.Value) (bool, error) {
+	var err error
+	switch {
+	case !dstRv.CanAddr() || m.opts.skips(MappableStage):
+		return false, nil
+	case isMappableFrom(dstRv.Type(), srcRv.Type()):
+		err = dstRv.Addr().Interface().(Mappable).MapFrom(srcRv.Interface(), m.Mapper)
+	case isMappableInto(dstRv.Type(), srcRv.Type()):
+		method, _ := findMethod(srcRv, "MapInto")
+		out := method.Call([]reflect.Value{dstRv.Addr(), reflect.ValueOf(m.Mapper)})
+		err = errorFromReflectValue(out[0])
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return true, ConversionError{Path: m.pathString(), ToType: dstRv.Type(), FromType: srcRv.Type(), Err: err}
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMoney struct {
+	Cents int64
+}
+
+type testMoneyDto struct {
+	Amount string
+}
+
+// Destination mapping itself
+func (md *testMoneyDto) MapFrom(src interface{}, m *Mapper) error {
+	money, ok := src.(testMoney)
+	if !ok {
+		return errors.New("not money")
+	}
+	if money.Cents < 0 {
+		return errors.New("negative amount")
+	}
+	md.Amount = strconv.FormatFloat(float64(money.Cents)/100, 'f', -1, 64)
+	return nil
+}
+
+type testTags []string
+
+// Source mapping itself
+func (tt testTags) MapInto(dst interface{}, m *Mapper) error {
+	out, ok := dst.(*string)
+	if !ok {
+		return errors.New("not a string")
+	}
+	*out = strings.Join(tt, ",")
+	return nil
+}
+
+// Mappable types take over their mapping
+func TestMappable(t *testing.T) {
+	type OrderDto struct {
+		Total testMoneyDto
+		Tags  string
+		Items []testMoneyDto
+	}
+	type Order struct {
+		Total *testMoney
+		Tags  testTags
+		Items []testMoney
+	}
+
+	var out OrderDto
+	err := Map(&out, Order{
+		Total: &testMoney{Cents: 250},
+		Tags:  testTags{"new", "paid"},
+		Items: []testMoney{{Cents: 100}, {Cents: 150}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, OrderDto{
+		Total: testMoneyDto{"2.5"},
+		Tags:  "new,paid",
+		Items: []testMoneyDto{{"1"}, {"1.5"}},
+	}, out)
+
+	// errors are wrapped with the path
+	err = Map(&out, Order{Items: []testMoney{{Cents: -1}}})
+	var convErr ConversionError
+	assert.True(t, errors.As(err, &convErr))
+	assert.Equal(t, "Items[0]", convErr.Path)
+
+	missing, err := (&Mapper{}).CheckCoverage(OrderDto{}, Order{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"reflect"
+	"time"
+)
+
+// MapStats describes a finished Map call
+type MapStats struct {
+	ToType   reflect.Type
+	FromType reflect.Type
+	Duration time.Duration
+	// Number of visited slice, array and map elements
+	Elements int
+	// Error returned by the call, if any
+	Err error
+}
+
+// MetricsSink receives metrics of Map calls
+// It must be safe for concurrent use if the Mapper is.
+type MetricsSink interface {
+	OnMapStart(toType, fromType reflect.Type)
+	OnMapEnd(stats MapStats)
+}
+
+// WithMetrics sets the sink that receives metrics of all Map calls
+func WithMetrics(sink MetricsSink) Option {
+	return func(o *options) {
+		o.metrics = sink
+	}
+}
+
+// Run a top level mapping function and report its metrics
+func (m *mapping) track(dstRv, srcRv reflect.Value, fn func() error) error {
+	if err := checkDestination(dstRv); err != nil {
+		return m.run(func() error { return err })
+	}
+	fn = m.withFinalizers(dstRv, srcRv, fn)
+	if m.opts.metrics == nil {
+		return m.run(fn)
+	}
+
+	m.opts.metrics.OnMapStart(dstRv.Type(), srcRv.Type())
+	start := time.Now()
+	err := m.run(fn)
+	m.opts.metrics.OnMapEnd(MapStats{
+		ToType:   dstRv.Type(),
+		FromType: srcRv.Type(),
+		Duration: time.Since(start),
+		Elements: m.elements,
+		Err:      err,
+	})
+	return err
+}
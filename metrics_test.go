@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetricsSink struct {
+	started []reflect.Type
+	stats   []MapStats
+}
+
+func (tms *testMetricsSink) OnMapStart(toType, fromType reflect.Type) {
+	tms.started = append(tms.started, toType)
+}
+
+func (tms *testMetricsSink) OnMapEnd(stats MapStats) {
+	tms.stats = append(tms.stats, stats)
+}
+
+// Metrics are reported for every Map call
+func TestMetrics(t *testing.T) {
+	var out struct {
+		Products []struct {
+			Name string
+		}
+	}
+	sink := &testMetricsSink{}
+	m := NewMapper(WithMetrics(sink))
+
+	err := m.Map(&out, ShoppingCart{Products: commonProducts})
+	assert.Nil(t, err)
+
+	var outName struct{ Name int }
+	err = m.Map(&outName, commonProducts[0])
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 2, len(sink.started))
+	assert.Equal(t, 2, len(sink.stats))
+
+	assert.Equal(t, reflect.TypeOf(out), sink.stats[0].ToType)
+	assert.Equal(t, reflect.TypeOf(ShoppingCart{}), sink.stats[0].FromType)
+	assert.Equal(t, 4, sink.stats[0].Elements)
+	assert.Nil(t, sink.stats[0].Err)
+
+	assert.Equal(t, reflect.TypeOf(outName), sink.started[1])
+	assert.Equal(t, err, sink.stats[1].Err)
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MustMap transfers values from src to dst like Map, but panics if mapping fails.
+// The panic message contains the path of the failed value and the conversion functions
+// registered for its types, which helps in init functions and tests.
+func (m *Mapper) MustMap(dst, src interface{}, opts ...Option) {
+	dstRv, srcRv := reflectValueRemovePtr(dst), reflectValueRemovePtr(src)
+	mp := m.newMapping(opts...)
+	err := mp.track(dstRv, srcRv, func() error {
+		return mp.mapValue(dstRv, srcRv)
+	})
+	if err != nil {
+		panic(mp.failureMessage(dstRv.Type(), srcRv.Type(), err))
+	}
+}
+
+// MustMap transfers values from src to dst and panics if mapping fails
+func MustMap(dst, src interface{}, opts ...Option) {
+	m := Mapper{}
+	m.MustMap(dst, src, opts...)
+}
+
+// TryMap transfers values from src to dst like Map and reports whether it succeeded,
+// for best-effort mapping where errors are not handled.
+// dst may be partially populated if mapping failed.
+func (m *Mapper) TryMap(dst, src interface{}, opts ...Option) bool {
+	return m.Map(dst, src, opts...) == nil
+}
+
+// TryMap transfers values from src to dst and reports whether it succeeded
+func TryMap(dst, src interface{}, opts ...Option) bool {
+	m := Mapper{}
+	return m.TryMap(dst, src, opts...)
+}
+
+// Format a detailed message of a failed Map call
+func (m *mapping) failureMessage(dstType, srcType reflect.Type, err error) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Failed to map %v to %v", typeName(srcType), typeName(dstType))
+	if m.errorPath != "" {
+		fmt.Fprintf(&sb, " at %v", m.errorPath)
+	}
+	fmt.Fprintf(&sb, ": %v", err)
+
+	// types of the failed value
+	var noMapping NoValidMappingError
+	var convErr ConversionError
+	switch {
+	case errors.As(err, &convErr):
+		dstType, srcType = convErr.ToType, convErr.FromType
+	case errors.As(err, &noMapping):
+		dstType, srcType = noMapping.ToType, noMapping.FromType
+	}
+
+	var considered []string
+	for _, conv := range m.converters {
+		if conv.From == srcType || conv.To == dstType {
+			considered = append(considered, fmt.Sprintf("\n\t%v -> %v (%v)", typeName(conv.From), typeName(conv.To), conv.Name))
+		}
+	}
+	if len(considered) == 0 {
+		fmt.Fprintf(&sb, "\nNo conversion functions registered from %v or to %v", typeName(srcType), typeName(dstType))
+	} else {
+		fmt.Fprintf(&sb, "\nConversion functions registered from %v or to %v:%v",
+			typeName(srcType), typeName(dstType), strings.Join(considered, ""))
+	}
+	return sb.String()
+}
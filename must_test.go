@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// MustMap panics with a detailed message
+func TestMustMap(t *testing.T) {
+	type ProductDto struct {
+		Name  string
+		Price int
+	}
+	type CartDto struct {
+		Products []ProductDto
+	}
+
+	var out CartDto
+	assert.NotPanics(t, func() {
+		MustMap(&out, ShoppingCart{Products: commonProducts[:1]})
+	})
+
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(price float32) (int, error) {
+		return 0, errors.New("bad price")
+	})
+	mapper.AddConvFunc(func(name string) []byte { return []byte(name) })
+	var message string
+	func() {
+		defer func() { message = fmt.Sprint(recover()) }()
+		mapper.MustMap(&out, ShoppingCart{Products: commonProducts[:1]})
+	}()
+	assert.Contains(t, message, "Failed to map dto.ShoppingCart to dto.CartDto at Products[0].Price: ")
+	assert.Contains(t, message, "bad price")
+	assert.Contains(t, message, "float32 -> int")
+	assert.NotContains(t, message, "string -> []uint8")
+}
+
+// TryMap reports success
+func TestTryMap(t *testing.T) {
+	var out struct{ Products []struct{ Name string } }
+	assert.True(t, TryMap(&out, ShoppingCart{Products: commonProducts[:1]}))
+	assert.Equal(t, commonProducts[0].Name, out.Products[0].Name)
+
+	var bad []int
+	assert.False(t, TryMap(&bad, ShoppingCart{}))
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SetNameMatcher sets a function that matches destination fields to source fields
+// of other names, like snake_case fields of database models to CamelCase DTO fields.
+// It receives the names of fields, or the names set by name tags, and applies to
+// destination fields without a source field of the same name. Source fields are tried
+// in order, so the first match wins. Pass nil to match only equal names.
+//
+//	mapper.SetNameMatcher(dto.MatchLooseNames)
+func (m *Mapper) SetNameMatcher(f func(dstName, srcName string) bool) {
+	m.updateRegistry(func(r *registry) {
+		r.nameMatcher = f
+	})
+}
+
+// MatchLooseNames is a name matcher that ignores case and underscores,
+// so user_id matches UserID and Created_At matches CreatedAt
+func MatchLooseNames(dstName, srcName string) bool {
+	return strings.EqualFold(strings.ReplaceAll(dstName, "_", ""), strings.ReplaceAll(srcName, "_", ""))
+}
+
+// WithJSONNames enables matching destination fields to source fields of other names
+// by the names of their json tags, like for generated API structs with json tags as the
+// source of truth. Fields without a json tag are matched by their names, fields with json:"-"
+// are not matched. Equal field names take precedence, the name matcher applies afterwards.
+func WithJSONNames(enabled bool) Option {
+	return func(o *options) {
+		o.jsonNames = enabled
+	}
+}
+
+// Get the json name of a field, falling back to its key.
+// Returns false if the field is ignored by encoding/json.
+func jsonName(tag reflect.StructTag, key string) (string, bool) {
+	name := strings.SplitN(tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return key, true
+	}
+	return name, true
+}
+
+// Find the key of the first source field that matches a destination field of another name,
+// by its json name if enabled or by the name matcher.
+// Returns false if there is no such field.
+func (m *mapping) matchSourceKey(srcType reflect.Type, key string, tag reflect.StructTag) (string, bool) {
+	if m.opts.jsonNames {
+		if name, ok := jsonName(tag, key); ok {
+			for _, info := range structFieldInfos(srcType) {
+				if srcName, ok := jsonName(info.rawTag, info.key); ok && srcName == name {
+					return info.key, true
+				}
+			}
+		}
+	}
+	if m.nameMatcher == nil {
+		return "", false
+	}
+	for _, info := range structFieldInfos(srcType) {
+		if m.nameMatcher(key, info.key) {
+			return info.key, true
+		}
+	}
+	return "", false
+}
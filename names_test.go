@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namesRow struct {
+	User_id    int
+	Created_at string
+	Full_name  string
+	Notes      string `dto:"ignore_out"`
+}
+
+type namesDto struct {
+	UserID    int
+	CreatedAt string
+	FullName  string
+	Name      string
+	Notes     string
+	Extra     map[string]interface{} `dto:"remain"`
+}
+
+// Fields of other names are matched by the name matcher
+func TestNameMatcher(t *testing.T) {
+	row := namesRow{User_id: 1, Created_at: "today", Full_name: "Bob", Notes: "hidden"}
+	mapper := Mapper{}
+	dto := namesDto{}
+	err := mapper.Map(&dto, row)
+	assert.Nil(t, err)
+	assert.Equal(t, namesDto{Extra: map[string]interface{}{"User_id": 1, "Created_at": "today", "Full_name": "Bob"}}, dto)
+
+	mapper.SetNameMatcher(MatchLooseNames)
+	dto = namesDto{}
+	err = mapper.Map(&dto, row)
+	assert.Nil(t, err)
+	assert.Equal(t, namesDto{UserID: 1, CreatedAt: "today", FullName: "Bob", Extra: map[string]interface{}{}}, dto)
+
+	// and in reverse
+	back := namesRow{}
+	err = mapper.Map(&back, dto)
+	assert.Nil(t, err)
+	assert.Equal(t, namesRow{User_id: 1, Created_at: "today", Full_name: "Bob"}, back)
+
+	diff, err := mapper.DiffFields(namesDto{}, namesRow{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{DestOnly: []string{"Name", "Notes"}}, diff)
+}
+
+// Equal names take precedence and the first matching source field wins
+func TestNameMatcherPrecedence(t *testing.T) {
+	mapper := Mapper{}
+	mapper.SetNameMatcher(func(dstName, srcName string) bool {
+		return strings.HasPrefix(srcName, dstName)
+	})
+	type Source struct {
+		NameFirst string
+		NameLast  string
+		ID        int
+		IDLegacy  int
+	}
+	var dto struct {
+		Name string
+		ID   int
+	}
+	err := mapper.Map(&dto, Source{NameFirst: "Bob", NameLast: "Gopher", ID: 1, IDLegacy: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob", dto.Name)
+	assert.Equal(t, 1, dto.ID)
+}
+
+// Fields of other names are matched by json names if enabled
+func TestJSONNames(t *testing.T) {
+	type ApiUser struct {
+		UserIdentifier int    `json:"id"`
+		DisplayName    string `json:"name,omitempty"`
+		Mail           string `json:"email"`
+		Secret         string `json:"-"`
+	}
+	type User struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Email  string
+		Token  string `json:"secret"`
+		Secret string `json:"-"`
+	}
+	from := ApiUser{UserIdentifier: 1, DisplayName: "Bob", Mail: "bob@mail.com", Secret: "s3cr3t"}
+
+	mapper := Mapper{}
+	user := User{}
+	err := mapper.Map(&user, from)
+	assert.Nil(t, err)
+	assert.Equal(t, User{Secret: "s3cr3t"}, user)
+
+	// fields without json tags are matched by their names
+	err = mapper.Map(&user, from, WithJSONNames(true))
+	assert.Nil(t, err)
+	assert.Equal(t, User{ID: 1, Name: "Bob", Email: "", Secret: "s3cr3t"}, user)
+
+	mapper.Configure(WithJSONNames(true))
+	diff, err := mapper.DiffFields(User{}, ApiUser{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{SourceOnly: []string{"Mail"}, DestOnly: []string{"Email", "Token"}}, diff)
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WithNumberFormat sets a fmt format for mapping numbers to strings, like %.2f for prices,
+// instead of converting integers to runes and failing for floats. Integers are formatted
+// as floats for floating point verbs and floats as integers for integer verbs, rounded
+// by the rounding policy. Fields tagged with format use their own format.
+func WithNumberFormat(format string) Option {
+	return func(o *options) {
+		o.numberFormat = format
+	}
+}
+
+// Get the verb of the first formatting directive, like f for %.2f
+// Returns 0 if there is none
+func formatVerb(format string) byte {
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j < len(format) && format[j] != '%' {
+			return format[j]
+		}
+		i = j
+	}
+	return 0
+}
+
+// Check if a format can be applied to numbers
+func checkNumberFormat(dstType reflect.Type, format string) error {
+	if formatVerb(format) == 0 {
+		return TagError{Tag: "format=" + format, Type: dstType, Reason: "no formatting verb"}
+	}
+	return nil
+}
+
+// Format a number to a string with the format of the current field or of the options
+// Returns false if the values are not a number and a string or there is no format
+func (m *mapping) formatNumber(dstRv, srcRv reflect.Value) (bool, error) {
+	if dstRv.Kind() != reflect.String || !isNumericKind(srcRv.Kind()) {
+		return false, nil
+	}
+	format := m.field.tags.format
+	if format == "" {
+		format = m.opts.numberFormat
+	}
+	if format == "" {
+		return false, nil
+	}
+	if err := checkNumberFormat(dstRv.Type(), format); err != nil {
+		return true, err
+	}
+
+	var value interface{}
+	switch verb := formatVerb(format); {
+	case strings.IndexByte("eEfFgG", verb) >= 0:
+		value = numberAsFloat(srcRv)
+	case strings.IndexByte("dboOxX", verb) >= 0 && srcRv.Kind() >= reflect.Float32:
+		value = int64(m.opts.roundFloat(reflect.Int64, srcRv).Float())
+	case srcRv.Kind() <= reflect.Int64:
+		value = srcRv.Int()
+	case srcRv.Kind() <= reflect.Uintptr:
+		value = srcRv.Uint()
+	default:
+		value = srcRv.Float()
+	}
+	dstRv.SetString(fmt.Sprintf(format, value))
+	return true, nil
+}
+
+// Get the value of a number as a float64
+func numberAsFloat(rv reflect.Value) float64 {
+	switch {
+	case rv.Kind() <= reflect.Int64:
+		return float64(rv.Int())
+	case rv.Kind() <= reflect.Uintptr:
+		return float64(rv.Uint())
+	}
+	return rv.Float()
+}
+
+// Get the number of decimal places of the precision tag of a float field
+func fieldPrecision(dstType reflect.Type, precision string) (int, error) {
+	if kind := derefType(dstType).Kind(); kind != reflect.Float32 && kind != reflect.Float64 {
+		return 0, TagError{Tag: "precision", Type: dstType, Reason: "not a float"}
+	}
+	places, err := strconv.Atoi(precision)
+	if err != nil || places < 0 {
+		return 0, TagError{Tag: "precision=" + precision, Type: dstType, Reason: "not a number of decimal places"}
+	}
+	return places, nil
+}
+
+// Round a mapped float field to the decimal places of its precision tag.
+// Halves are rounded away from zero, or to even with RoundHalfEven.
+func (m *mapping) roundPrecision(dstRv reflect.Value, precision string) error {
+	places, err := fieldPrecision(dstRv.Type(), precision)
+	if err != nil {
+		return err
+	}
+	for dstRv.Kind() == reflect.Ptr {
+		if dstRv.IsNil() {
+			return nil
+		}
+		dstRv = dstRv.Elem()
+	}
+	scale := math.Pow10(places)
+	scaled := dstRv.Float() * scale
+	if m.opts.rounding == RoundHalfEven {
+		scaled = math.RoundToEven(scaled)
+	} else {
+		scaled = math.Round(scaled)
+	}
+	dstRv.SetFloat(scaled / scale)
+	return nil
+}
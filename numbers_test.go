@@ -0,0 +1,142 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type numbersProduct struct {
+	Price    float64
+	Discount float32
+	Stock    int
+	Weight   float64
+	Sizes    []float64
+}
+
+type numbersProductDto struct {
+	Price    string
+	Discount string `dto:"format=-%.0f%%"`
+	Stock    string
+	Weight   string `dto:"format=%d g"`
+	Sizes    []string
+}
+
+// Numbers are formatted to strings by the format of fields or options
+func TestNumberFormat(t *testing.T) {
+	product := numbersProduct{Price: 9.5, Discount: 15, Stock: 3, Weight: 249.5, Sizes: []float64{1, 1.5}}
+	mapper := Mapper{}
+
+	dto := numbersProductDto{}
+	err := mapper.Map(&dto, product, WithNumberFormat("%.2f"))
+	assert.Nil(t, err)
+	assert.Equal(t, numbersProductDto{
+		Price:    "9.50",
+		Discount: "-15%",
+		Stock:    "3.00",
+		Weight:   "249 g",
+		Sizes:    []string{"1.00", "1.50"},
+	}, dto)
+
+	// integer verbs round floats by the rounding policy
+	err = mapper.Map(&dto, product, WithNumberFormat("%d"), WithRoundingPolicy(RoundHalfUp))
+	assert.Nil(t, err)
+	assert.Equal(t, numbersProductDto{
+		Price:    "10",
+		Discount: "-15%",
+		Stock:    "3",
+		Weight:   "250 g",
+		Sizes:    []string{"1", "2"},
+	}, dto)
+
+	// without a format, floats can't be mapped to strings
+	err = mapper.Map(&dto, product)
+	assert.True(t, errors.As(err, &NoValidMappingError{}))
+
+	mapper.Configure(WithNumberFormat("%v"))
+	diff, err := mapper.DiffFields(numbersProductDto{}, numbersProduct{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+}
+
+// Formats have to contain a verb
+func TestNumberFormatErrors(t *testing.T) {
+	type BadDto struct {
+		Price string `dto:"format=price"`
+	}
+	bad := BadDto{}
+	err := Map(&bad, struct{ Price float64 }{Price: 1})
+	assert.Equal(t, TagError{Tag: "format=price", Type: stringRfType, Reason: "no formatting verb"}, err)
+
+	mapper := Mapper{}
+	mapper.RegisterPair(BadDto{}, struct{ Price float64 }{})
+	err = mapper.Validate()
+	var ce ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.Len(t, ce, 1)
+	assert.Equal(t, TagError{Tag: "format=price", Type: stringRfType, Reason: "no formatting verb"}, ce[0].Err)
+}
+
+// Float fields with the precision tag are rounded to decimal places
+func TestPrecisionTag(t *testing.T) {
+	type Invoice struct {
+		Net   float64
+		Tax   float64
+		Total float32
+		Rate  *float64
+		Raw   float64
+	}
+	type InvoiceDto struct {
+		Net   float64  `dto:"precision=2"`
+		Tax   float64  `dto:"precision=2"`
+		Total float64  `dto:"precision=0"`
+		Rate  *float64 `dto:"precision=1,max=1"`
+		Raw   float64
+	}
+	rate := 0.96
+	from := Invoice{Net: 10.125, Tax: -1.005001, Total: 12.5, Rate: &rate, Raw: 1.23456}
+
+	dto := InvoiceDto{}
+	err := Map(&dto, from)
+	assert.Nil(t, err)
+	assert.Equal(t, 10.13, dto.Net)
+	assert.Equal(t, -1.01, dto.Tax)
+	assert.Equal(t, 13.0, dto.Total)
+	assert.Equal(t, 1.0, *dto.Rate)
+	assert.Equal(t, 1.23456, dto.Raw)
+
+	// halves are rounded to even with RoundHalfEven
+	mapper := Mapper{}
+	err = mapper.Map(&dto, from, WithRoundingPolicy(RoundHalfEven))
+	assert.Nil(t, err)
+	assert.Equal(t, 10.12, dto.Net)
+	assert.Equal(t, 12.0, dto.Total)
+}
+
+// Precision tags need a float field and a number of decimal places
+func TestPrecisionTagErrors(t *testing.T) {
+	type BadDto struct {
+		Count int     `dto:"precision=2"`
+		Price float64 `dto:"precision=two"`
+	}
+	from := struct {
+		Count int
+		Price float64
+	}{}
+
+	mapper := Mapper{}
+	mapper.RegisterPair(BadDto{}, from)
+	err := mapper.Validate()
+	var ce ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.ElementsMatch(t, []error{
+		TagError{Tag: "precision", Type: reflect.TypeOf(0), Reason: "not a float"},
+		TagError{Tag: "precision=two", Type: reflect.TypeOf(0.0), Reason: "not a number of decimal places"},
+	}, []error{ce[0].Err, ce[1].Err})
+
+	bad := BadDto{}
+	err = Map(&bad, from)
+	assert.True(t, errors.As(err, &TagError{}))
+}
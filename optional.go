@@ -0,0 +1,203 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+)
+
+// OptionalType describes a family of optional wrapper types, like Option[T] of a library,
+// so they are mapped to and from their values. Absent values are handled by the absent policy,
+// but are mapped to absent values of optional destinations.
+type OptionalType struct {
+	// Match reports whether a type belongs to the family, for example by SameGeneric
+	Match func(rfType reflect.Type) bool
+	// Elem returns the type of values of an optional type
+	Elem func(rfType reflect.Type) reflect.Type
+	// Get returns the value of an optional and whether it is present
+	Get func(opt reflect.Value) (reflect.Value, bool)
+	// Make creates an optional of the given type from a value, or an absent one if value is invalid.
+	// Optionals can be used only as sources if it is nil.
+	Make func(rfType reflect.Type, value reflect.Value) reflect.Value
+}
+
+// AddOptionalType adds a family of optional types. Structs with a bool Valid field and a
+// single value field, like sql.NullString, are recognized without registration.
+// Types with methods like IsSome() bool and Value() T are added with OptionalMethods.
+func (m *Mapper) AddOptionalType(ot OptionalType) {
+	m.updateRegistry(func(r *registry) {
+		r.optionalTypes = append(r.optionalTypes, ot)
+	})
+}
+
+// Cache of optional types recognized without registration, by type
+var optionalCache sync.Map
+
+// Access to values of an optional type
+type optionalAccess struct {
+	elem reflect.Type
+	get  func(opt reflect.Value) (reflect.Value, bool)
+	// nil if the type can't be created
+	make func(value reflect.Value) reflect.Value
+	// recognized by a Valid field
+	inferred bool
+}
+
+// Find out how to access values of an optional type
+// Returns nil if the type is not optional
+func (r *registry) optionalOf(rfType reflect.Type) *optionalAccess {
+	for i := range r.optionalTypes {
+		if !r.optionalTypes[i].Match(rfType) {
+			continue
+		}
+		ot := r.optionalTypes[i]
+		access := &optionalAccess{elem: ot.Elem(rfType), get: ot.Get}
+		if ot.Make != nil {
+			access.make = func(value reflect.Value) reflect.Value {
+				return ot.Make(rfType, value)
+			}
+		}
+		return access
+	}
+	if access, ok := optionalCache.Load(rfType); ok {
+		return access.(*optionalAccess)
+	}
+	var access *optionalAccess
+	if valid, ok := validFieldOptional(rfType); ok {
+		access = &valid
+	}
+	optionalCache.Store(rfType, access)
+	return access
+}
+
+// Access optional structs with a bool Valid field and a single value field, like sql.NullString
+func validFieldOptional(rfType reflect.Type) (optionalAccess, bool) {
+	if rfType.Kind() != reflect.Struct || rfType.NumField() != 2 {
+		return optionalAccess{}, false
+	}
+	validField, ok := rfType.FieldByName("Valid")
+	if !ok || validField.Type.Kind() != reflect.Bool {
+		return optionalAccess{}, false
+	}
+	valueIndex := 1 - validField.Index[0]
+	valueField := rfType.Field(valueIndex)
+	if valueField.PkgPath != "" {
+		return optionalAccess{}, false
+	}
+	return optionalAccess{
+		elem: valueField.Type,
+		get: func(opt reflect.Value) (reflect.Value, bool) {
+			return opt.Field(valueIndex), opt.Field(validField.Index[0]).Bool()
+		},
+		make: func(value reflect.Value) reflect.Value {
+			opt := reflect.New(rfType).Elem()
+			if value.IsValid() {
+				opt.Field(valueIndex).Set(value)
+				opt.Field(validField.Index[0]).SetBool(true)
+			}
+			return opt
+		},
+		inferred: true,
+	}, true
+}
+
+// OptionalMethods describes optional types with a method that reports whether a value
+// is present and a method that returns it, like IsSome() bool and Value() T, so they can be
+// registered with AddOptionalType. Methods can have value or pointer receivers.
+// Such optionals can be used only as sources.
+//
+//	mapper.AddOptionalType(dto.OptionalMethods("IsSome", "Value"))
+func OptionalMethods(present, value string) OptionalType {
+	return OptionalType{
+		Match: func(rfType reflect.Type) bool {
+			if rfType.Kind() == reflect.Ptr || rfType.Kind() == reflect.Interface {
+				return false
+			}
+			ptrType := reflect.PtrTo(rfType)
+			presentMethod, ok := ptrType.MethodByName(present)
+			if !ok || presentMethod.Type.NumIn() != 1 || presentMethod.Type.NumOut() != 1 ||
+				presentMethod.Type.Out(0).Kind() != reflect.Bool {
+				return false
+			}
+			valueMethod, ok := ptrType.MethodByName(value)
+			return ok && valueMethod.Type.NumIn() == 1 && valueMethod.Type.NumOut() == 1
+		},
+		Elem: func(rfType reflect.Type) reflect.Type {
+			method, _ := reflect.PtrTo(rfType).MethodByName(value)
+			return method.Type.Out(0)
+		},
+		Get: func(opt reflect.Value) (reflect.Value, bool) {
+			presentMethod, _ := findMethod(opt, present)
+			if !presentMethod.Call(nil)[0].Bool() {
+				return reflect.Value{}, false
+			}
+			valueMethod, _ := findMethod(opt, value)
+			return valueMethod.Call(nil)[0], true
+		},
+	}
+}
+
+// Find out how optional source and destination types are accessed. Structs recognized
+// by their Valid field are not optional if the other type is a non-optional struct,
+// so that they are still mapped field by field.
+func (r *registry) optionalPair(dstType, srcType reflect.Type) (dstOpt, srcOpt *optionalAccess) {
+	if dstType == srcType {
+		return nil, nil
+	}
+	if access := r.optionalOf(dstType); access != nil && access.make != nil {
+		dstOpt = access
+	}
+	srcOpt = r.optionalOf(srcType)
+	if dstOpt != nil && dstOpt.inferred && srcOpt == nil && srcType.Kind() == reflect.Struct {
+		dstOpt = nil
+	}
+	if srcOpt != nil && srcOpt.inferred && dstOpt == nil && dstType.Kind() == reflect.Struct {
+		srcOpt = nil
+	}
+	return dstOpt, srcOpt
+}
+
+// Map optional sources to their values and values to optional destinations
+// Returns false if neither is optional
+func (m *mapping) mapOptional(dstRv, srcRv reflect.Value) (bool, error) {
+	dstOpt, srcOpt := m.optionalPair(dstRv.Type(), srcRv.Type())
+
+	// unwrap sources
+	if srcOpt != nil {
+		value, present := srcOpt.get(srcRv)
+		switch {
+		case present:
+			return true, m.mapUnwrapped(dstRv, value)
+		case dstOpt != nil:
+			dstRv.Set(dstOpt.make(reflect.Value{}))
+			return true, nil
+		}
+		return true, m.mapAbsent(dstRv, srcRv)
+	}
+
+	// wrap values
+	if dstOpt != nil {
+		value := reflect.New(dstOpt.elem).Elem()
+		if err := m.mapValue(value, srcRv); err != nil {
+			return true, err
+		}
+		dstRv.Set(dstOpt.make(value))
+		return true, nil
+	}
+	return false, nil
+}
+
+// Map an absent optional source by the absent policy
+func (m *mapping) mapAbsent(dstRv, srcRv reflect.Value) error {
+	switch m.opts.absentPolicy {
+	case ZeroAbsent:
+		dstRv.Set(reflect.Zero(dstRv.Type()))
+	case RejectAbsent:
+		return AbsentValueError{
+			ToType:   dstRv.Type(),
+			FromType: srcRv.Type(),
+		}
+	default:
+		m.skipped = true
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package dto
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Optional with IsSome() bool and Value() T methods, which must be registered
+type testMaybe struct {
+	value   string
+	present bool
+}
+
+func (tm testMaybe) IsSome() bool {
+	return tm.present
+}
+
+func (tm *testMaybe) Value() string {
+	return tm.value
+}
+
+// Plain struct with a Get() (T, bool) method, which is not an optional
+type testStore struct {
+	Name  string
+	Items map[int]int
+}
+
+func (ts *testStore) Get() (int, bool) {
+	item, ok := ts.Items[0]
+	return item, ok
+}
+
+// Optional with a single pointer field, which must be registered
+type testOption[T any] struct {
+	Some *T
+}
+
+// Structs with a Valid field are mapped to and from their values
+func TestOptionalValidField(t *testing.T) {
+	type UserDto struct {
+		Name  string
+		Email *string
+		Age   sql.NullInt64
+	}
+	type User struct {
+		Name  sql.NullString
+		Email sql.NullString
+		Age   int
+	}
+
+	var out UserDto
+	err := Map(&out, User{
+		Name:  sql.NullString{String: "Alice", Valid: true},
+		Email: sql.NullString{String: "alice@mail.com", Valid: true},
+		Age:   30,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Alice", out.Name)
+	assert.Equal(t, "alice@mail.com", *out.Email)
+	assert.Equal(t, sql.NullInt64{Int64: 30, Valid: true}, out.Age)
+
+	var back User
+	err = Map(&back, UserDto{Name: "Bob"})
+	assert.Nil(t, err)
+	assert.Equal(t, sql.NullString{String: "Bob", Valid: true}, back.Name)
+	assert.Equal(t, sql.NullString{}, back.Email)
+}
+
+// Absent values are handled by the absent policy, but stay absent in optionals
+func TestOptionalAbsent(t *testing.T) {
+	type UserDto struct {
+		Name  string
+		Email *string
+		Phone testOptional[string]
+	}
+	type User struct {
+		Name  sql.NullString
+		Email sql.NullString
+		Phone sql.NullString
+	}
+	email := "alice@mail.com"
+	filled := UserDto{Name: "Alice", Email: &email, Phone: testOptional[string]{Value: "123", Valid: true}}
+
+	{
+		out := filled
+		err := Map(&out, User{})
+		assert.Nil(t, err)
+		assert.Equal(t, "Alice", out.Name)
+		assert.Equal(t, &email, out.Email)
+		assert.Zero(t, out.Phone)
+	}
+	{
+		out := filled
+		err := Map(&out, User{}, WithAbsentPolicy(ZeroAbsent))
+		assert.Nil(t, err)
+		assert.Zero(t, out)
+	}
+	{
+		out := filled
+		err := Map(&out, User{}, WithAbsentPolicy(RejectAbsent))
+		assert.Equal(t, AbsentValueError{ToType: stringRfType, FromType: reflect.TypeOf(sql.NullString{})}, err)
+	}
+	{
+		// the nil policy doesn't apply
+		out := filled
+		err := Map(&out, User{}, WithNilPolicy(RejectNil))
+		assert.Nil(t, err)
+		assert.Equal(t, "Alice", out.Name)
+	}
+}
+
+// Types with registered methods can be sources
+func TestOptionalMethods(t *testing.T) {
+	type Out struct {
+		Name  string
+		Alias *string
+	}
+	src := struct {
+		Name  testMaybe
+		Alias testMaybe
+	}{Name: testMaybe{"Alice", true}}
+
+	var out Out
+	err := Map(&out, src)
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+
+	mapper := NewMapper()
+	mapper.AddOptionalType(OptionalMethods("IsSome", "Value"))
+	out = Out{}
+	err = mapper.Map(&out, src)
+	assert.Nil(t, err)
+	assert.Equal(t, "Alice", out.Name)
+	assert.Nil(t, out.Alias)
+}
+
+// Structs with a Get() (T, bool) method are still mapped field by field
+func TestOptionalGetMethod(t *testing.T) {
+	type StoreDto struct {
+		Name string
+	}
+	var out StoreDto
+	err := Map(&out, testStore{Name: "Corner", Items: map[int]int{0: 1}})
+	assert.Nil(t, err)
+	assert.Equal(t, StoreDto{Name: "Corner"}, out)
+}
+
+// Registered optional types are mapped in both directions
+func TestAddOptionalType(t *testing.T) {
+	mapper := NewMapper()
+	mapper.AddOptionalType(OptionalType{
+		Match: func(rfType reflect.Type) bool {
+			return SameGeneric(rfType, testOption[int]{})
+		},
+		Elem: func(rfType reflect.Type) reflect.Type {
+			return rfType.Field(0).Type.Elem()
+		},
+		Get: func(opt reflect.Value) (reflect.Value, bool) {
+			ptr := opt.Field(0)
+			if ptr.IsNil() {
+				return reflect.Value{}, false
+			}
+			return ptr.Elem(), true
+		},
+		Make: func(rfType reflect.Type, value reflect.Value) reflect.Value {
+			opt := reflect.New(rfType).Elem()
+			if value.IsValid() {
+				opt.Field(0).Set(reflect.New(value.Type()))
+				opt.Field(0).Elem().Set(value)
+			}
+			return opt
+		},
+	})
+
+	type Product struct {
+		Name  string
+		Price int
+	}
+	type ProductDto struct {
+		Name  testOption[string]
+		Price testOption[float64]
+	}
+
+	var out ProductDto
+	err := mapper.Map(&out, Product{Name: "Shirt", Price: 20})
+	assert.Nil(t, err)
+	assert.Equal(t, "Shirt", *out.Name.Some)
+	assert.Equal(t, 20.0, *out.Price.Some)
+
+	var back Product
+	err = mapper.Map(&back, ProductDto{Price: out.Price})
+	assert.Nil(t, err)
+	assert.Equal(t, Product{Price: 20}, back)
+}
+
+// Structs with a Valid field are still mapped field by field to plain structs
+func TestOptionalPlainStruct(t *testing.T) {
+	type Account struct {
+		ID    int
+		Valid bool
+	}
+	type AccountDto struct {
+		ID    int
+		Valid bool
+		Name  string
+	}
+	var out AccountDto
+	err := Map(&out, Account{ID: 1, Valid: true})
+	assert.Nil(t, err)
+	assert.Equal(t, AccountDto{ID: 1, Valid: true}, out)
+}
@@ -0,0 +1,442 @@
+package dto
+
+import (
+	"context"
+	"math"
+	"reflect"
+)
+
+// Option configures the behaviour of a Mapper
+type Option func(*options)
+
+// AssignPolicy defines how directly assignable values are transferred
+type AssignPolicy int
+
+const (
+	// ShareAssignable assigns values directly, so pointers, slices and maps
+	// are shared between source and destination. This is the default.
+	ShareAssignable AssignPolicy = iota
+	// CopyAssignable maps values recursively, so that no references are shared
+	CopyAssignable
+)
+
+// NilPolicy defines how nil source pointers are handled,
+// if they have to be dereferenced or are assigned to interfaces.
+// Pointers assignable to pointers are not affected.
+type NilPolicy int
+
+const (
+	// SkipNil leaves the destination untouched. This is the default.
+	SkipNil NilPolicy = iota
+	// ZeroNil sets the destination to its zero value
+	ZeroNil
+	// RejectNil fails mapping with a NilValueError
+	RejectNil
+	// DropNil removes nil elements when mapping slices, keeping the order of
+	// the remaining ones. Other nil values are skipped like with SkipNil.
+	// Directly assignable slices are not affected.
+	DropNil
+)
+
+// AbsentPolicy defines how absent values of optional sources are handled,
+// if the destination is not optional. Absent values are mapped to absent optionals regardless.
+type AbsentPolicy int
+
+const (
+	// SkipAbsent leaves the destination untouched. This is the default.
+	SkipAbsent AbsentPolicy = iota
+	// ZeroAbsent sets the destination to its zero value
+	ZeroAbsent
+	// RejectAbsent fails mapping with an AbsentValueError
+	RejectAbsent
+)
+
+// OpaquePolicy defines how values of kinds that have no data to map are handled:
+// functions, channels and unsafe pointers. Conversion functions still apply to them.
+type OpaquePolicy int
+
+const (
+	// SkipOpaque leaves the destination untouched, if either value is of such a kind.
+	// This is the default.
+	SkipOpaque OpaquePolicy = iota
+	// RejectOpaque fails mapping with an OpaqueValueError
+	RejectOpaque
+	// ShareOpaque assigns or converts values like any others, so they are shared.
+	// Values that can't be assigned fail with a NoValidMappingError.
+	ShareOpaque
+)
+
+// UnwrapPolicy defines how slices are mapped to single values
+type UnwrapPolicy int
+
+const (
+	// NoUnwrap doesn't map slices to single values. This is the default.
+	NoUnwrap UnwrapPolicy = iota
+	// UnwrapSingle maps the element of single element slices,
+	// slices with multiple elements fail with an AmbiguousSliceError
+	UnwrapSingle
+	// UnwrapFirst maps the first element of slices
+	UnwrapFirst
+)
+
+// UpdatePolicy defines how destinations that already hold data are updated.
+// Except with WriteOnce, struct fields with a source are overwritten, as are slices, maps
+// and assignable values.
+type UpdatePolicy int
+
+const (
+	// MergeExisting keeps struct fields without a source and maps into the values
+	// of non-nil destination pointers, which might be shared. This is the default.
+	MergeExisting UpdatePolicy = iota
+	// ReallocPointers keeps struct fields without a source, but allocates new values
+	// for destination pointers, so values they pointed to are never modified
+	ReallocPointers
+	// ReplaceExisting resets destination structs before mapping, so the result
+	// is the same as if a new value was mapped into
+	ReplaceExisting
+	// ReuseExisting works like MergeExisting, but also reuses slices with enough capacity
+	// and non-nil maps, which minimizes allocations when repeatedly mapping into the same value.
+	// Elements of reused slices are merged like structs, entries of reused maps are replaced.
+	ReuseExisting
+	// WriteOnce works like MergeExisting, but never overwrites struct fields with non-zero
+	// values, so the first source wins when a destination is assembled from multiple sources.
+	// Nested structs and values of non-nil struct pointers are merged field by field.
+	WriteOnce
+)
+
+// RoundingPolicy defines how floating point values are converted to integers
+type RoundingPolicy int
+
+const (
+	// TruncateFloats drops the fraction like a Go conversion, so 2.7 becomes 2. This is the default.
+	TruncateFloats RoundingPolicy = iota
+	// RoundHalfUp rounds to the nearest integer and halves away from zero like math.Round,
+	// so 2.5 becomes 3 and -2.5 becomes -3
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest integer and halves to the even one like math.RoundToEven,
+	// so 2.5 becomes 2 and 3.5 becomes 4. This is also known as banker's rounding.
+	RoundHalfEven
+)
+
+// Mapper options
+type options struct {
+	assignPolicy  map[reflect.Kind]AssignPolicy
+	nilPolicy     NilPolicy
+	absentPolicy  AbsentPolicy
+	opaquePolicy  OpaquePolicy
+	updatePolicy  UpdatePolicy
+	unwrapPolicy  UnwrapPolicy
+	rounding      RoundingPolicy
+	jsonNames     bool
+	numberFormat  string
+	wrapValues    bool
+	emptyAsNil    bool
+	strictTypes   bool
+	pageFields    *PageFields
+	scope         string
+	metrics       MetricsSink
+	locale        *Locale
+	shadowHandler func(ShadowedConversion)
+	ctx           context.Context
+	progress      func(elements int)
+	progressEvery int
+	injectValues  map[string]interface{}
+	convFuncs     []callConvFunc
+
+	conflictHandler func(FieldConflict) error
+	audit           *AuditLog
+	skipStages      Stage
+	inspectSkipped  bool
+
+	unexportedFields  bool
+	unexportedSources bool
+}
+
+// Kinds that hold references and can be copied by policy
+var referenceKinds = []reflect.Kind{reflect.Ptr, reflect.Slice, reflect.Map}
+
+// ==================================== Options ===============================
+
+// WithAssignPolicy sets the policy for assignable values of the given kinds.
+// Only pointers, slices and maps are affected, if no kinds are given,
+// the policy is applied to all of them.
+func WithAssignPolicy(policy AssignPolicy, kinds ...reflect.Kind) Option {
+	if len(kinds) == 0 {
+		kinds = referenceKinds
+	}
+	return func(o *options) {
+		if o.assignPolicy == nil {
+			o.assignPolicy = make(map[reflect.Kind]AssignPolicy)
+		}
+		for _, kind := range kinds {
+			o.assignPolicy[kind] = policy
+		}
+	}
+}
+
+// WithNilPolicy sets the policy for nil source pointers.
+// It is applied at every pointer level, so **T sources are handled alike.
+func WithNilPolicy(policy NilPolicy) Option {
+	return func(o *options) {
+		o.nilPolicy = policy
+	}
+}
+
+// WithAbsentPolicy sets the policy for absent values of optional sources
+func WithAbsentPolicy(policy AbsentPolicy) Option {
+	return func(o *options) {
+		o.absentPolicy = policy
+	}
+}
+
+// WithOpaquePolicy sets the policy for functions, channels and unsafe pointers
+func WithOpaquePolicy(policy OpaquePolicy) Option {
+	return func(o *options) {
+		o.opaquePolicy = policy
+	}
+}
+
+// WithUpdatePolicy sets the policy for destinations that already hold data
+func WithUpdatePolicy(policy UpdatePolicy) Option {
+	return func(o *options) {
+		o.updatePolicy = policy
+	}
+}
+
+// WithUnwrapPolicy sets the policy for mapping slices to single values.
+// Empty slices are handled like nil pointers by the nil policy.
+// Fields tagged with single or first use the according policy regardless.
+func WithUnwrapPolicy(policy UnwrapPolicy) Option {
+	return func(o *options) {
+		o.unwrapPolicy = policy
+	}
+}
+
+// WithRoundingPolicy sets the policy for converting floating point values to integers.
+// Conversion functions and kind converters take precedence.
+func WithRoundingPolicy(policy RoundingPolicy) Option {
+	return func(o *options) {
+		o.rounding = policy
+	}
+}
+
+// WithWrapValues enables mapping single values to single element slices.
+// Fields tagged with wrap are mapped this way regardless.
+func WithWrapValues(enabled bool) Option {
+	return func(o *options) {
+		o.wrapValues = enabled
+	}
+}
+
+// WithEmptyAsNil enables mapping empty values to nil pointers instead of
+// pointers to empty values. Zero scalars as well as empty slices and maps are
+// considered empty, structs are not.
+func WithEmptyAsNil(enabled bool) Option {
+	return func(o *options) {
+		o.emptyAsNil = enabled
+	}
+}
+
+// WithStrictTypes enables strict type identity for conversion functions.
+// Conversion functions are applied only if their argument or result is a
+// defined type, so functions between predeclared or unnamed types
+// like string and []byte don't affect all values of those types.
+//
+// Type aliases (type RawPassword = string) are identical to the types they denote,
+// use defined types (type RawPassword string) instead.
+func WithStrictTypes(enabled bool) Option {
+	return func(o *options) {
+		o.strictTypes = enabled
+	}
+}
+
+// WithScope selects the scope of conversion functions added with AddScopedConvFunc.
+// Scoped functions take precedence over unscoped ones.
+// Usually passed to a single Map call.
+func WithScope(scope string) Option {
+	return func(o *options) {
+		o.scope = scope
+	}
+}
+
+// Conversion function passed with WithConv
+type callConvFunc struct {
+	fromType reflect.Type
+	toType   reflect.Type
+	fun      convertFuncClosure
+	name     string
+}
+
+// WithConv adds a conversion function for a single Map call, like for endpoint specific
+// formatting. It takes precedence over all registered functions, as well as over
+// previous ones for the same type pair.
+//
+// Panics if f is not a valid conversion function
+func WithConv(f interface{}) Option {
+	inType, outType, closure := makeConvFuncClosure(f)
+	return func(o *options) {
+		// don't share the backing array with the options copied from
+		o.convFuncs = append(o.convFuncs[:len(o.convFuncs):len(o.convFuncs)],
+			callConvFunc{fromType: inType, toType: outType, fun: closure, name: funcName(f)})
+	}
+}
+
+// Find a conversion function passed with WithConv
+func (o *options) findCallConvFunc(dstType, srcType reflect.Type) (convertFuncClosure, bool) {
+	for i := len(o.convFuncs) - 1; i >= 0; i-- {
+		if cf := o.convFuncs[i]; cf.fromType == srcType && cf.toType == dstType {
+			return cf.fun, true
+		}
+	}
+	return nil, false
+}
+
+// WithUnexportedFields enables setting unexported destination fields, for mapping
+// into structs that can't be changed. Unexported fields are skipped by default.
+// They are mapped from source fields of the same name, or of the exported form
+// of their name, so id is mapped from Id.
+//
+// This bypasses the visibility rules of Go with package unsafe,
+// so the destination package should not rely on invariants of those fields.
+func WithUnexportedFields(enabled bool) Option {
+	return func(o *options) {
+		o.unexportedFields = enabled
+	}
+}
+
+// WithUnexportedSources enables reading unexported source fields of basic kinds,
+// like the storage of ID types. They are mapped to destination fields of the same name,
+// or of the exported form of their name, so id is mapped to Id.
+// Values are copied, so no references to unexported memory are shared.
+func WithUnexportedSources(enabled bool) Option {
+	return func(o *options) {
+		o.unexportedSources = enabled
+	}
+}
+
+// Copy options, so they can be modified independently
+func (o options) clone() options {
+	if o.assignPolicy != nil {
+		assignPolicy := make(map[reflect.Kind]AssignPolicy, len(o.assignPolicy))
+		for kind, policy := range o.assignPolicy {
+			assignPolicy[kind] = policy
+		}
+		o.assignPolicy = assignPolicy
+	}
+	return o
+}
+
+// ==================================== Policy checks =========================
+
+// Check if a kind has no data to map
+func isOpaqueKind(kind reflect.Kind) bool {
+	return kind == reflect.Func || kind == reflect.Chan || kind == reflect.UnsafePointer
+}
+
+// Check if an assignable value of the given kind has to be copied.
+// Structs and arrays are copied if any reference kind is copied,
+// because they might contain references.
+func (o *options) copiesKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return o.assignPolicy[kind] == CopyAssignable
+	case reflect.Struct, reflect.Array:
+		for _, policy := range o.assignPolicy {
+			if policy == CopyAssignable {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Round a floating point value that is converted to an integer kind by the rounding policy
+func (o *options) roundFloat(dstKind reflect.Kind, srcRv reflect.Value) reflect.Value {
+	if !isIntegerKind(dstKind) || (srcRv.Kind() != reflect.Float32 && srcRv.Kind() != reflect.Float64) {
+		return srcRv
+	}
+	var rounded float64
+	switch o.rounding {
+	case RoundHalfUp:
+		rounded = math.Round(srcRv.Float())
+	case RoundHalfEven:
+		rounded = math.RoundToEven(srcRv.Float())
+	default:
+		return srcRv
+	}
+	return reflect.ValueOf(rounded).Convert(srcRv.Type())
+}
+
+// Check if non-nil destination pointers are mapped into
+func (o *options) reusesPointers() bool {
+	return o.updatePolicy == MergeExisting || o.updatePolicy == ReuseExisting || o.updatePolicy == WriteOnce
+}
+
+// Check if a destination struct field is already written and kept by WriteOnce.
+// Structs with exported fields and non-nil pointers to them are merged instead.
+func (o *options) keepsWritten(rv reflect.Value) bool {
+	if o.updatePolicy != WriteOnce || rv.IsZero() {
+		return false
+	}
+	return !o.mergesWritten(rv) && !(rv.Kind() == reflect.Ptr && o.mergesWritten(rv.Elem()))
+}
+
+// Check if a written destination struct is merged field by field with WriteOnce
+func (o *options) mergesWritten(rv reflect.Value) bool {
+	if o.updatePolicy != WriteOnce || rv.Kind() != reflect.Struct || rv.IsZero() {
+		return false
+	}
+	for _, info := range structFieldInfos(rv.Type()) {
+		if info.exported {
+			return true
+		}
+	}
+	return false
+}
+
+// ==================================== Mapper configuration ==================
+
+// NewMapper creates a Mapper with the given options
+func NewMapper(opts ...Option) *Mapper {
+	m := &Mapper{}
+	m.Configure(opts...)
+	return m
+}
+
+// Load the current options of the Mapper
+func (m *Mapper) loadOptions() options {
+	return *m.loadState().opts
+}
+
+// Configure applies options to the Mapper
+// Options can also be passed to single Map calls
+//
+// Options are replaced atomically, so it is safe to call Configure
+// while the Mapper is in use. Running Map calls keep their options.
+func (m *Mapper) Configure(opts ...Option) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.loadState()
+	o := s.opts.clone()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	m.state.Store(&mapperState{reg: s.reg, opts: &o})
+}
+
+// Reconfigure atomically replaces all functions and options of the Mapper
+// with those of next, which is usually prepared from a reloaded configuration:
+//
+//	next := dto.NewMapper(opts...)
+//	next.AddConvFunc(...)
+//	mapper.Reconfigure(next)
+//
+// Running Map calls keep the previous configuration, later calls use the new one.
+// Functions and options are swapped together, so no call sees a mix of both.
+// Later changes to either Mapper don't affect the other.
+func (m *Mapper) Reconfigure(next *Mapper) {
+	s := next.loadState()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state.Store(&s)
+}
@@ -0,0 +1,507 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Assignable slices, maps and pointers are shared by default
+func TestAssignPolicyShare(t *testing.T) {
+	from := ShoppingCart{Products: []Product{commonProducts[0]}}
+	var to ShoppingCart
+
+	err := Map(&to, from)
+	assert.Nil(t, err)
+
+	from.Products[0].Name = "Changed"
+	assert.Equal(t, "Changed", to.Products[0].Name)
+}
+
+// Assignable references are copied with CopyAssignable
+func TestAssignPolicyCopy(t *testing.T) {
+	type Catalog struct {
+		Cart   ShoppingCart
+		Tagged map[string][]Product
+		Best   *Product
+	}
+	best := commonProducts[1]
+	from := Catalog{
+		Cart:   ShoppingCart{Products: []Product{commonProducts[0]}},
+		Tagged: map[string][]Product{"US": {commonProducts[3]}},
+		Best:   &best,
+	}
+	var to Catalog
+
+	m := NewMapper(WithAssignPolicy(CopyAssignable))
+	err := m.Map(&to, from)
+	assert.Nil(t, err)
+	assert.Equal(t, from, to)
+
+	from.Cart.Products[0].Name = "Changed"
+	from.Tagged["US"][0].Name = "Changed"
+	from.Best.Name = "Changed"
+	assert.Equal(t, commonProducts[0].Name, to.Cart.Products[0].Name)
+	assert.Equal(t, commonProducts[3].Name, to.Tagged["US"][0].Name)
+	assert.Equal(t, commonProducts[1].Name, to.Best.Name)
+}
+
+// Copy policy applies only to the given kinds
+func TestAssignPolicyPerKind(t *testing.T) {
+	best := commonProducts[1]
+	from := struct {
+		Products []Product
+		Best     *Product
+	}{Products: []Product{commonProducts[0]}, Best: &best}
+	var to struct {
+		Products []Product
+		Best     *Product
+	}
+
+	m := NewMapper(WithAssignPolicy(CopyAssignable, reflect.Slice))
+	err := m.Map(&to, from)
+	assert.Nil(t, err)
+
+	assert.Same(t, from.Best, to.Best)
+	assert.NotSame(t, &from.Products[0], &to.Products[0])
+}
+
+// Nil policies are applied at every pointer level
+func TestNilPolicy(t *testing.T) {
+	var nilProd *Product
+	from := struct {
+		Prod  *Product
+		Inner **Product
+	}{Prod: nil, Inner: &nilProd}
+	type SubProduct struct {
+		Name string
+	}
+	type to struct {
+		Prod  *SubProduct
+		Inner Product
+	}
+	filled := to{Prod: &SubProduct{Name: "Filled"}, Inner: commonProducts[1]}
+
+	{
+		out := filled
+		err := NewMapper(WithNilPolicy(SkipNil)).Map(&out, from)
+		assert.Nil(t, err)
+		assert.Equal(t, filled, out)
+	}
+	{
+		out := filled
+		err := NewMapper(WithNilPolicy(ZeroNil)).Map(&out, from)
+		assert.Nil(t, err)
+		assert.Nil(t, out.Prod)
+		assert.Zero(t, out.Inner)
+	}
+	{
+		out := filled
+		err := NewMapper(WithNilPolicy(RejectNil)).Map(&out, from)
+		assert.ErrorAs(t, err, &NilValueError{})
+	}
+}
+
+// Functions and channels are skipped by default, shared or rejected by policy
+func TestOpaquePolicy(t *testing.T) {
+	type Handler struct {
+		Name     string
+		OnChange func()
+		Events   chan int
+	}
+	type HandlerDto struct {
+		Name     string
+		OnChange func()
+		Events   <-chan int
+	}
+	from := Handler{Name: "Save", OnChange: func() {}, Events: make(chan int)}
+
+	{
+		var out HandlerDto
+		err := Map(&out, from)
+		assert.Nil(t, err)
+		assert.Equal(t, "Save", out.Name)
+		assert.Nil(t, out.OnChange)
+		assert.Nil(t, out.Events)
+	}
+	{
+		var out HandlerDto
+		err := Map(&out, from, WithOpaquePolicy(ShareOpaque))
+		assert.Nil(t, err)
+		assert.NotNil(t, out.OnChange)
+		assert.NotNil(t, out.Events)
+	}
+	{
+		var out HandlerDto
+		err := Map(&out, from, WithOpaquePolicy(RejectOpaque))
+		assert.ErrorAs(t, err, &OpaqueValueError{})
+	}
+	{
+		var out struct{ OnChange string }
+		err := Map(&out, from, WithOpaquePolicy(ShareOpaque))
+		assert.ErrorAs(t, err, &NoValidMappingError{})
+	}
+}
+
+// Nil elements are dropped from slices with DropNil
+func TestDropNil(t *testing.T) {
+	type ProductDto struct {
+		Name string
+		Link string
+	}
+	shirt, shoes := commonProducts[0], commonProducts[1]
+	shoesPtr := &shoes
+	var nilPtr *Product
+	from := struct {
+		Products []*Product
+		Nested   []**Product
+	}{[]*Product{nil, &shirt, nil, &shoes}, []**Product{&nilPtr, &shoesPtr}}
+
+	var out struct {
+		Products []ProductDto
+		Nested   []*ProductDto
+	}
+	err := NewMapper(WithNilPolicy(DropNil)).Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, []ProductDto{{Name: shirt.Name}, {Name: shoes.Name}}, out.Products)
+	assert.Equal(t, []*ProductDto{{Name: shoes.Name}}, out.Nested)
+}
+
+// Populated destinations are updated by the update policy
+func TestUpdatePolicy(t *testing.T) {
+	type ProductDto struct {
+		Name string
+		Link string
+	}
+	type CartDto struct {
+		Owner    string
+		Featured *ProductDto
+		Products []ProductDto
+	}
+	from := struct {
+		Featured *Product
+		Products []Product
+	}{&commonProducts[0], commonProducts[1:2]}
+
+	populated := func() (CartDto, *ProductDto) {
+		featured := &ProductDto{Name: "old", Link: "old-link"}
+		return CartDto{
+			Owner:    "owner",
+			Featured: featured,
+			Products: []ProductDto{{Name: "a", Link: "a-link"}, {Name: "b", Link: "b-link"}},
+		}, featured
+	}
+
+	// fields without source are kept and pointers reused
+	out, featured := populated()
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, "owner", out.Owner)
+	assert.Same(t, featured, out.Featured)
+	assert.Equal(t, ProductDto{Name: commonProducts[0].Name, Link: "old-link"}, *featured)
+	assert.Equal(t, []ProductDto{{Name: commonProducts[1].Name}}, out.Products)
+
+	// pointers are reallocated
+	out, featured = populated()
+	err = Map(&out, from, WithUpdatePolicy(ReallocPointers))
+	assert.Nil(t, err)
+	assert.Equal(t, "owner", out.Owner)
+	assert.Equal(t, ProductDto{Name: "old", Link: "old-link"}, *featured)
+	assert.Equal(t, ProductDto{Name: commonProducts[0].Name}, *out.Featured)
+
+	// destinations are reset
+	out, featured = populated()
+	err = Map(&out, from, WithUpdatePolicy(ReplaceExisting))
+	assert.Nil(t, err)
+	assert.Equal(t, CartDto{
+		Featured: &ProductDto{Name: commonProducts[0].Name},
+		Products: []ProductDto{{Name: commonProducts[1].Name}},
+	}, out)
+	assert.Equal(t, "old", featured.Name)
+}
+
+// Allocations are reused with ReuseExisting
+func TestReuseExisting(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+	type CartDto struct {
+		Featured *ProductDto
+		Products []ProductDto
+		Prices   map[string]float64
+	}
+	type Cart struct {
+		Featured *Product
+		Products []Product
+		Prices   map[string]float32
+	}
+	mapper := NewMapper(WithUpdatePolicy(ReuseExisting))
+
+	var out CartDto
+	err := mapper.Map(&out, Cart{&commonProducts[0], commonProducts[:3], map[string]float32{"a": 1, "b": 2}})
+	assert.Nil(t, err)
+	featured, products, prices := out.Featured, &out.Products[0], reflect.ValueOf(out.Prices).Pointer()
+
+	err = mapper.Map(&out, Cart{&commonProducts[1], commonProducts[1:3], map[string]float32{"c": 3}})
+	assert.Nil(t, err)
+	assert.Same(t, featured, out.Featured)
+	assert.Same(t, products, &out.Products[0])
+	assert.Equal(t, reflect.ValueOf(out.Prices).Pointer(), prices)
+	assert.Equal(t, CartDto{
+		Featured: &ProductDto{commonProducts[1].Name},
+		Products: []ProductDto{{commonProducts[1].Name}, {commonProducts[2].Name}},
+		Prices:   map[string]float64{"c": 3},
+	}, out)
+
+	// slices without enough capacity are reallocated
+	err = mapper.Map(&out, Cart{Products: commonProducts[:4]})
+	assert.Nil(t, err)
+	assert.NotSame(t, products, &out.Products[0])
+	assert.Len(t, out.Products, 4)
+}
+
+// The first source wins with WriteOnce
+func TestWriteOnce(t *testing.T) {
+	type Address struct {
+		City   string
+		Street string
+	}
+	type ProfileDto struct {
+		Name     string
+		Email    string
+		Tags     []string
+		Address  Address
+		Shipping *Address
+	}
+	type Account struct {
+		Name     string
+		Tags     []string
+		Address  Address
+		Shipping *Address
+	}
+	type Contact struct {
+		Name     string
+		Email    string
+		Tags     []string
+		Address  Address
+		Shipping *Address
+	}
+
+	var out ProfileDto
+	err := Map(&out, Account{Name: "alice", Address: Address{City: "Berlin"}, Shipping: &Address{City: "Berlin"}},
+		WithUpdatePolicy(WriteOnce))
+	assert.Nil(t, err)
+	shipping := out.Shipping
+	err = Map(&out, Contact{
+		Name:     "Alice",
+		Email:    "alice@mail.com",
+		Tags:     []string{"new"},
+		Address:  Address{City: "Paris", Street: "Main St"},
+		Shipping: &Address{City: "Paris", Street: "Side St"},
+	}, WithUpdatePolicy(WriteOnce))
+	assert.Nil(t, err)
+	assert.Equal(t, ProfileDto{
+		Name:     "alice",
+		Email:    "alice@mail.com",
+		Tags:     []string{"new"},
+		Address:  Address{City: "Berlin", Street: "Main St"},
+		Shipping: &Address{City: "Berlin", Street: "Side St"},
+	}, out)
+	assert.Same(t, shipping, out.Shipping)
+}
+
+// Empty values are mapped to nil pointers with WithEmptyAsNil
+func TestEmptyAsNil(t *testing.T) {
+	from := struct {
+		Name  string
+		Price float32
+		Tags  []string
+	}{}
+	var out struct {
+		Name  *string
+		Price *float32
+		Tags  *[]string
+	}
+
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.NotNil(t, out.Name)
+	assert.NotNil(t, out.Price)
+
+	out.Name, out.Price = nil, nil
+	err = NewMapper(WithEmptyAsNil(true)).Map(&out, from)
+	assert.Nil(t, err)
+	assert.Nil(t, out.Name)
+	assert.Nil(t, out.Price)
+	assert.Nil(t, out.Tags)
+}
+
+// Conversion functions between universal types are skipped with strict types
+func TestStrictTypes(t *testing.T) {
+	type HashedPassword string
+	type Credentials struct {
+		Name     string
+		Password HashedPassword
+	}
+	from := struct {
+		Name     string
+		Password string
+	}{"Bob", "Secret"}
+
+	m := Mapper{}
+	m.AddConvFunc(func(p RawPassword) string {
+		return "hash:" + p
+	})
+	m.AddConvFunc(func(p RawPassword) HashedPassword {
+		return HashedPassword("hash:" + p)
+	})
+
+	var out Credentials
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, "hash:Bob", out.Name)
+
+	m.Configure(WithStrictTypes(true))
+	err = m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob", out.Name)
+	assert.Equal(t, HashedPassword("hash:Secret"), out.Password)
+}
+
+// Floats are converted to integers by the rounding policy
+func TestRoundingPolicy(t *testing.T) {
+	type Score float32
+	type Result struct {
+		Points []float64
+		Score  Score
+		Bonus  *float64
+	}
+	type ResultDto struct {
+		Points []int
+		Score  uint8
+		Bonus  int64
+	}
+	bonus := -2.5
+	from := Result{Points: []float64{2.5, 3.5, -2.5, 2.7, -0.4}, Score: 4.5, Bonus: &bonus}
+
+	m := Mapper{}
+	var out ResultDto
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, ResultDto{Points: []int{2, 3, -2, 2, 0}, Score: 4, Bonus: -2}, out)
+
+	err = m.Map(&out, from, WithRoundingPolicy(RoundHalfUp))
+	assert.Nil(t, err)
+	assert.Equal(t, ResultDto{Points: []int{3, 4, -3, 3, 0}, Score: 5, Bonus: -3}, out)
+
+	err = m.Map(&out, from, WithRoundingPolicy(RoundHalfEven))
+	assert.Nil(t, err)
+	assert.Equal(t, ResultDto{Points: []int{2, 4, -2, 3, 0}, Score: 4, Bonus: -2}, out)
+
+	// floats are not affected
+	var f struct{ Score float64 }
+	err = m.Map(&f, from, WithRoundingPolicy(RoundHalfUp))
+	assert.Nil(t, err)
+	assert.Equal(t, 4.5, f.Score)
+}
+
+// Options passed to Map apply only to this call
+func TestCallOptions(t *testing.T) {
+	from := ShoppingCart{Products: []Product{commonProducts[0]}}
+	var out ShoppingCart
+
+	m := NewMapper(WithAssignPolicy(CopyAssignable, reflect.Ptr))
+	err := m.Map(&out, from, WithAssignPolicy(CopyAssignable, reflect.Slice))
+	assert.Nil(t, err)
+	assert.NotSame(t, &from.Products[0], &out.Products[0])
+
+	err = m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Same(t, &from.Products[0], &out.Products[0])
+}
+
+// Conversion functions passed to Map take precedence for this call
+func TestWithConv(t *testing.T) {
+	type PriceDto struct {
+		Price string
+	}
+	m := NewMapper()
+	m.AddConvFunc(func(p float32) string {
+		return fmt.Sprintf("%.2f", p)
+	})
+	m.AddScopedConvFunc("de", func(p float32) string {
+		return fmt.Sprintf("%.2f €", p)
+	})
+	euros := WithConv(func(p float32) string {
+		return fmt.Sprintf("EUR %.2f", p)
+	})
+
+	var out PriceDto
+	err := m.Map(&out, commonProducts[0], WithScope("de"), euros)
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR 9.40", out.Price)
+
+	err = m.Map(&out, commonProducts[0], euros, WithConv(func(p float32) (string, error) {
+		return "", errors.New("no price")
+	}))
+	assert.ErrorAs(t, err, &ConversionError{})
+
+	err = m.Map(&out, commonProducts[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "9.40", out.Price)
+}
+
+// Functions and options are replaced together while the Mapper is in use
+func TestReconfigure(t *testing.T) {
+	type Source struct {
+		Price float32
+		Prod  **Product
+	}
+	type Target struct {
+		Price string
+		Prod  *Product
+	}
+	configs := make([]*Mapper, 2)
+	for i, policy := range []NilPolicy{RejectNil, ZeroNil} {
+		name := []string{"reject", "zero"}[i]
+		configs[i] = NewMapper(WithNilPolicy(policy))
+		configs[i].AddConvFunc(func(price float32) string { return name })
+	}
+
+	m := NewMapper()
+	m.Reconfigure(configs[0])
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				var out Target
+				err := m.Map(&out, Source{})
+				// every call sees the function and the nil policy of the same configuration
+				if out.Price == "reject" {
+					assert.ErrorAs(t, err, &NilValueError{})
+				} else {
+					assert.Equal(t, "zero", out.Price)
+					assert.Nil(t, err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		m.Reconfigure(configs[i%2])
+	}
+	wg.Wait()
+
+	// later changes to the configuration don't affect the Mapper
+	configs[1].AddConvFunc(func(price float32) string { return "changed" })
+	var out Target
+	err := m.Map(&out, Source{})
+	assert.Nil(t, err)
+	assert.Equal(t, "zero", out.Price)
+}
@@ -0,0 +1,198 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// State of a single Map call
+type mapping struct {
+	*Mapper
+	*registry
+	opts options
+	path []pathSegment
+	// number of mapped collection elements
+	elements int
+	// number of elements at the last progress check and the next report
+	stepped, nextProgress int
+	// innermost destination struct type
+	owner reflect.Type
+	// innermost destination struct field
+	field structField
+	// cached results of cacheable conversion functions
+	convCache map[convCacheKey]reflect.Value
+	// collected constraint violations
+	violations ValidationErrors
+	// path of the innermost failed value
+	errorPath    string
+	hasErrorPath bool
+	// index of the current source and the sources that populated fields by path,
+	// nil unless mapping with MapSources
+	sourceIndex int
+	written     map[string]int
+	// whether the last mapped value was an unwrapped source of its destination
+	unwrapped bool
+	// whether the current value is left untouched by a skipped source, and the last mapped one was
+	skipped, lastSkipped bool
+}
+
+// Segment of a destination path: a field name, a slice index or a map key
+type pathSegment struct {
+	field string
+	index int
+	key   reflect.Value
+}
+
+// Inspect function restricted to destination paths
+type pathInspectFunc struct {
+	pattern  []string
+	toType   reflect.Type
+	fromType reflect.Type
+	fun      inspectFuncClosure
+}
+
+// Create mapping state for a Map call with call options
+func (m *Mapper) newMapping(opts ...Option) *mapping {
+	s := m.loadState()
+	mp := &mapping{Mapper: m, registry: s.reg, opts: *s.opts}
+	if len(opts) > 0 {
+		mp.opts = mp.opts.clone()
+		for _, opt := range opts {
+			opt(&mp.opts)
+		}
+	}
+	return mp
+}
+
+// ==================================== Path tracking =========================
+
+func (m *mapping) pushField(name string) {
+	m.path = append(m.path, pathSegment{field: name})
+}
+
+func (m *mapping) pushIndex(index int) {
+	m.elements++
+	m.path = append(m.path, pathSegment{index: index})
+}
+
+func (m *mapping) pushKey(key reflect.Value) {
+	m.elements++
+	m.path = append(m.path, pathSegment{key: key})
+}
+
+func (m *mapping) popPath() {
+	m.path = m.path[:len(m.path)-1]
+}
+
+// Format a path segment as in Products, [2] or [key]
+func (ps pathSegment) String() string {
+	switch {
+	case ps.field != "":
+		return ps.field
+	case ps.key.IsValid():
+		return fmt.Sprintf("[%v]", ps.key.Interface())
+	}
+	return "[" + strconv.Itoa(ps.index) + "]"
+}
+
+// Format the current destination path as in Products[2].Price
+func (m *mapping) pathString() string {
+	var sb strings.Builder
+	for i, segment := range m.path {
+		if i > 0 && segment.field != "" {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(segment.String())
+	}
+	return sb.String()
+}
+
+// ==================================== Path patterns =========================
+
+// Split a path pattern into field names and bracket segments
+func splitPathPattern(pattern string) []string {
+	var tokens []string
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '.':
+			pattern = pattern[1:]
+		case '[':
+			end := strings.IndexByte(pattern, ']')
+			if end < 0 {
+				end = len(pattern) - 1
+			}
+			tokens = append(tokens, pattern[:end+1])
+			pattern = pattern[end+1:]
+		default:
+			end := strings.IndexAny(pattern, ".[")
+			if end < 0 {
+				end = len(pattern)
+			}
+			tokens = append(tokens, pattern[:end])
+			pattern = pattern[end:]
+		}
+	}
+	return tokens
+}
+
+// Check if the current destination path matches a split pattern.
+// A * matches any field name, [*] matches any index or key.
+func (m *mapping) pathMatches(pattern []string) bool {
+	if len(pattern) != len(m.path) {
+		return false
+	}
+	for i, token := range pattern {
+		segment := m.path[i]
+		isBracket := strings.HasPrefix(token, "[")
+		switch {
+		case isBracket != (segment.field == ""):
+			return false
+		case token == "*" || token == "[*]":
+			continue
+		case token != segment.String():
+			return false
+		}
+	}
+	return true
+}
+
+// Run inspect functions restricted to the current path for (dst-src) pair.
+// Functions without a source are skipped if they already ran for an unwrapped source.
+func (m *mapping) runPathInspectFuncs(dstRv, srcRv reflect.Value, unwrapped bool) error {
+	for _, pf := range m.pathFunc {
+		if pf.toType != dstRv.Type() || (pf.fromType != nilRecvRfType && pf.fromType != srcRv.Type()) {
+			continue
+		}
+		if unwrapped && pf.fromType == nilRecvRfType {
+			continue
+		}
+		if !m.pathMatches(pf.pattern) {
+			continue
+		}
+		if err := pf.fun(dstRv.Addr(), srcRv, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddInspectFuncAt adds an inspection function that runs only for destination values
+// at paths matching the pattern. Paths are relative to the mapped value and consist
+// of field names, slice indices and map keys, like Products[2].Price.
+// A * matches any field name, [*] matches any index or key, like Products[*].Price.
+//
+// Panics if f is not a valid inspection function
+func (m *Mapper) AddInspectFuncAt(pattern string, f interface{}) {
+	inType, fromType, closure := makeInspectFuncClosure(f)
+	m.updateRegistry(func(r *registry) {
+		r.hooks = append(r.hooks, HookInfo{To: inType, From: hookSourceType(fromType), Pattern: pattern, Name: funcName(f)})
+		r.pathFunc = append(r.pathFunc, pathInspectFunc{
+			pattern:  splitPathPattern(pattern),
+			toType:   inType,
+			fromType: fromType,
+			fun:      closure,
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Inspect functions restricted to destination paths
+func TestInspectFuncAt(t *testing.T) {
+	var out struct {
+		Products []struct {
+			Name  string
+			Price float32
+		}
+		Featured struct {
+			Name  string
+			Price float32
+		}
+	}
+	from := struct {
+		Products []Product
+		Featured Product
+	}{commonProducts, commonProducts[0]}
+
+	m := Mapper{}
+	m.AddInspectFuncAt("Products[*].Price", func(price *float32) {
+		*price = 0
+	})
+	m.AddInspectFuncAt("Products[1].Name", func(name *string, from string) {
+		*name = "Second " + from
+	})
+
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+
+	for _, product := range out.Products {
+		assert.Zero(t, product.Price)
+	}
+	assert.Equal(t, "Second Shoes", out.Products[1].Name)
+	assert.Equal(t, "Hat", out.Products[2].Name)
+	assert.Equal(t, commonProducts[0].Price, out.Featured.Price)
+}
+
+// Path patterns match field names, indices and map keys
+func TestPathPatterns(t *testing.T) {
+	m := (&Mapper{}).newMapping()
+	m.pushField("Products")
+	m.pushKey(reflect.ValueOf("US"))
+	m.pushIndex(2)
+	m.pushField("Name")
+
+	assert.Equal(t, "Products[US][2].Name", m.pathString())
+	assert.True(t, m.pathMatches(splitPathPattern("Products[US][2].Name")))
+	assert.True(t, m.pathMatches(splitPathPattern("Products[*][*].Name")))
+	assert.True(t, m.pathMatches(splitPathPattern("*[*][2].*")))
+	assert.False(t, m.pathMatches(splitPathPattern("Products[*].Name")))
+	assert.False(t, m.pathMatches(splitPathPattern("Products.US[2].Name")))
+}
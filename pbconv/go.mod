@@ -0,0 +1,19 @@
+module github.com/dranikpg/dto-mapper/pbconv
+
+go 1.18
+
+require (
+	github.com/dranikpg/dto-mapper v0.0.0-20261016155758-702c5af4c6e2
+	github.com/stretchr/testify v1.7.0
+	google.golang.org/protobuf v1.28.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
+
+// Local development against the dto package in the parent directory,
+// the replace is ignored by modules that require pbconv
+replace github.com/dranikpg/dto-mapper => ../
@@ -0,0 +1,117 @@
+// Package pbconv provides conversion functions between JSON-like values
+// and protobuf well-known types. It is a separate module, so dto doesn't depend on protobuf.
+package pbconv
+
+import (
+	"reflect"
+
+	dto "github.com/dranikpg/dto-mapper"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// AddStructConvFuncs adds conversion functions between map[string]interface{} and
+// *structpb.Struct, []interface{} and *structpb.ListValue, as well as interface{}
+// and *structpb.Value. Values that structpb doesn't support, like structs or typed
+// slices, are first mapped into maps and slices by m, so its conversion functions apply.
+func AddStructConvFuncs(m *dto.Mapper) {
+	m.AddConvFunc(func(src map[string]interface{}, m *dto.Mapper) (*structpb.Struct, error) {
+		if src == nil {
+			return nil, nil
+		}
+		return toStruct(m, src)
+	})
+	m.AddConvFunc(func(src []interface{}, m *dto.Mapper) (*structpb.ListValue, error) {
+		if src == nil {
+			return nil, nil
+		}
+		return toList(m, src)
+	})
+	m.AddConvFunc(func(src interface{}, m *dto.Mapper) (*structpb.Value, error) {
+		return toValue(m, src)
+	})
+
+	m.AddConvFunc(func(src *structpb.Struct) map[string]interface{} {
+		if src == nil {
+			return nil
+		}
+		return src.AsMap()
+	})
+	m.AddConvFunc(func(src *structpb.ListValue) []interface{} {
+		if src == nil {
+			return nil
+		}
+		return src.AsSlice()
+	})
+	m.AddConvFunc(func(src *structpb.Value) interface{} {
+		return src.AsInterface()
+	})
+}
+
+// Convert a map into a struct value by value
+func toStruct(m *dto.Mapper, src map[string]interface{}) (*structpb.Struct, error) {
+	out := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(src))}
+	for key, value := range src {
+		pbValue, err := toValue(m, value)
+		if err != nil {
+			return nil, err
+		}
+		out.Fields[key] = pbValue
+	}
+	return out, nil
+}
+
+// Convert a slice into a list value by value
+func toList(m *dto.Mapper, src []interface{}) (*structpb.ListValue, error) {
+	out := &structpb.ListValue{Values: make([]*structpb.Value, len(src))}
+	for i, value := range src {
+		pbValue, err := toValue(m, value)
+		if err != nil {
+			return nil, err
+		}
+		out.Values[i] = pbValue
+	}
+	return out, nil
+}
+
+// Convert a dynamic value into a protobuf value. Structs, maps and slices of other types
+// are mapped into map[string]interface{} or []interface{} first.
+func toValue(m *dto.Mapper, src interface{}) (*structpb.Value, error) {
+	switch src := src.(type) {
+	case map[string]interface{}:
+		pbStruct, err := toStruct(m, src)
+		if err != nil {
+			return nil, err
+		}
+		return structpb.NewStructValue(pbStruct), nil
+	case []interface{}:
+		list, err := toList(m, src)
+		if err != nil {
+			return nil, err
+		}
+		return structpb.NewListValue(list), nil
+	}
+
+	switch rv := reflect.ValueOf(src); rv.Kind() {
+	case reflect.Struct, reflect.Map:
+		var fields map[string]interface{}
+		if err := m.Map(&fields, src); err != nil {
+			return nil, err
+		}
+		return toValue(m, fields)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		var values []interface{}
+		if err := m.Map(&values, src); err != nil {
+			return nil, err
+		}
+		return toValue(m, values)
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return structpb.NewNullValue(), nil
+		}
+		return toValue(m, rv.Elem().Interface())
+	}
+	return structpb.NewValue(src)
+}
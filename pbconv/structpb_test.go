@@ -0,0 +1,61 @@
+package pbconv
+
+import (
+	"testing"
+
+	dto "github.com/dranikpg/dto-mapper"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type testAddress struct {
+	City string `dto:"key=city"`
+	Zip  int    `dto:"key=zip"`
+}
+
+// JSON-like maps are converted into structpb values and back
+func TestStructConvFuncs(t *testing.T) {
+	mapper := dto.NewMapper()
+	AddStructConvFuncs(mapper)
+
+	type Request struct {
+		Attributes map[string]interface{}
+		Tags       []interface{}
+		Extra      interface{}
+	}
+	type RequestPb struct {
+		Attributes *structpb.Struct
+		Tags       *structpb.ListValue
+		Extra      *structpb.Value
+	}
+	from := Request{
+		Attributes: map[string]interface{}{
+			"name":    "Alice",
+			"age":     30,
+			"address": testAddress{City: "Berlin", Zip: 10115},
+			"scores":  []int{1, 2},
+			"manager": nil,
+		},
+		Tags:  []interface{}{"new", true},
+		Extra: map[string]string{"source": "web"},
+	}
+
+	var out RequestPb
+	err := mapper.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":    "Alice",
+		"age":     30.0,
+		"address": map[string]interface{}{"city": "Berlin", "zip": 10115.0},
+		"scores":  []interface{}{1.0, 2.0},
+		"manager": nil,
+	}, out.Attributes.AsMap())
+	assert.Equal(t, []interface{}{"new", true}, out.Tags.AsSlice())
+	assert.Equal(t, map[string]interface{}{"source": "web"}, out.Extra.AsInterface())
+
+	var back Request
+	err = mapper.Map(&back, out)
+	assert.Nil(t, err)
+	assert.Equal(t, "Berlin", back.Attributes["address"].(map[string]interface{})["city"])
+	assert.Equal(t, from.Tags, back.Tags)
+}
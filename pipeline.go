@@ -0,0 +1,66 @@
+package dto
+
+// Stage is a set of optional steps of the mapping pipeline, which can be skipped with WithSkipStages.
+//
+// Every value is mapped by the first step that applies to it, in this order:
+//
+//  1. Conversion functions (ConvertStage)
+//  2. Decode hooks, which replace the source and continue (DecodeStage)
+//  3. Mappable and MappableSource implementations (MappableStage)
+//  4. json.RawMessage copies and opaque values
+//  5. Direct assignment, or struct by struct mapping if fields need to be tracked
+//  6. Type conversion
+//  7. Unwrapping source pointers, interfaces, snapshots, results and optionals,
+//     which continues the pipeline for the same destination
+//  8. Destination pointers, wrapping, collections and structs, which map their elements
+//
+// Once a value is mapped without errors, inspection functions run for it (InspectStage),
+// exactly once, with the source the destination was mapped from, before decode hooks.
+// Values left untouched because their source was skipped, like nil pointers with SkipNil
+// or opaque values with SkipOpaque, are not inspected unless enabled with WithInspectSkipped.
+// Nested values are inspected before the values that contain them.
+// Finalizers run last, once the root value is mapped completely (FinalizeStage).
+type Stage uint
+
+const (
+	// ConvertStage runs conversion functions, including ones passed with WithConv
+	ConvertStage Stage = 1 << iota
+	// DecodeStage runs decode hooks
+	DecodeStage
+	// MappableStage runs Mappable and MappableSource implementations
+	MappableStage
+	// InspectStage runs inspection functions, including ones restricted to paths
+	InspectStage
+	// FinalizeStage runs finalizers on the root value
+	FinalizeStage
+)
+
+// WithSkipStages skips the given stages of the mapping pipeline, like for mapping raw values
+// for debugging or for comparing results with and without custom functions.
+// Values are mapped by the next step that applies to them.
+//
+//	mapper.Map(&dto, src, WithSkipStages(InspectStage|FinalizeStage))
+func WithSkipStages(stages Stage) Option {
+	return func(o *options) {
+		o.skipStages = stages
+	}
+}
+
+// WithInspectSkipped enables running inspection functions for values that were left untouched
+// because their source was skipped, like for filling in defaults for nil pointers.
+// Inspection functions can tell them apart with FieldInfo.Skipped.
+func WithInspectSkipped(enabled bool) Option {
+	return func(o *options) {
+		o.inspectSkipped = enabled
+	}
+}
+
+// Check if a stage of the pipeline is skipped
+func (o *options) skips(stage Stage) bool {
+	return o.skipStages&stage != 0
+}
+
+// Check if inspection functions run for a value, that is possibly skipped
+func (o *options) inspects(skipped bool) bool {
+	return !o.skips(InspectStage) && (!skipped || o.inspectSkipped)
+}
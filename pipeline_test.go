@@ -0,0 +1,196 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stageCents struct {
+	Value int
+}
+
+type stageItem struct {
+	Price stageCents
+}
+
+type stageItemDto struct {
+	Price string
+}
+
+type stageOrder struct {
+	Item  *stageItem
+	Items []*stageItem
+	Note  interface{}
+}
+
+type stageOrderDto struct {
+	Item  stageItemDto
+	Items []stageItemDto
+	Note  string
+}
+
+// Conversion, assignment and inspection run in order, nested values first
+func TestPipelineOrder(t *testing.T) {
+	var steps []string
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(c stageCents) string {
+		steps = append(steps, "convert")
+		return fmt.Sprintf("$%v", c.Value)
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto) {
+		steps = append(steps, "inspect item "+dto.Price)
+	})
+	mapper.AddInspectFunc(func(dto *stageOrderDto) {
+		steps = append(steps, "inspect order")
+	})
+
+	dto := stageOrderDto{}
+	err := mapper.Map(&dto, stageOrder{Item: &stageItem{Price: stageCents{5}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"convert", "inspect item $5", "inspect order"}, steps)
+}
+
+// Inspection functions run once per value, even if the source is unwrapped
+func TestPipelineInspectOnce(t *testing.T) {
+	counts := map[string]int{}
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(c stageCents) string {
+		return fmt.Sprint(c.Value)
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto) {
+		counts["item"]++
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto, src *stageItem) {
+		counts["pointer"]++
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto, src stageItem) {
+		counts["value"]++
+	})
+	mapper.AddInspectFuncAt("Note", func(dto *string) {
+		counts["note"]++
+	})
+	mapper.AddInspectFuncAt("Items[*]", func(dto *stageItemDto) {
+		counts["path"]++
+	})
+
+	dto := stageOrderDto{}
+	err := mapper.Map(&dto, stageOrder{
+		Item:  &stageItem{},
+		Items: []*stageItem{{}, {}},
+		Note:  "fragile",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "fragile", dto.Note)
+
+	// functions for both the pointer and the value source run
+	assert.Equal(t, map[string]int{"item": 3, "pointer": 3, "value": 3, "note": 1, "path": 2}, counts)
+}
+
+// Inspection functions and finalizers don't run for failed values
+func TestPipelineInspectFailure(t *testing.T) {
+	inspected := false
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(c stageCents) (string, error) {
+		return "", errors.New("negative")
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto) {
+		inspected = true
+	})
+	mapper.AddFinalizer(func(dto *stageOrderDto) {
+		inspected = true
+	})
+
+	dto := stageOrderDto{}
+	err := mapper.Map(&dto, stageOrder{Item: &stageItem{}})
+	assert.NotNil(t, err)
+	assert.False(t, inspected)
+}
+
+// Skipped stages don't run, values are mapped by the next step
+func TestSkipStages(t *testing.T) {
+	var steps []string
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(c stageCents) string {
+		steps = append(steps, "convert")
+		return "converted"
+	})
+	mapper.AddDecodeHook(func(from, to reflect.Kind, value interface{}) (interface{}, error) {
+		if from == reflect.Interface {
+			steps = append(steps, "decode")
+		}
+		return value, nil
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto) {
+		steps = append(steps, "inspect")
+	})
+	mapper.AddFinalizer(func(dto *stageOrderDto) {
+		steps = append(steps, "finalize")
+	})
+	src := stageOrder{Item: &stageItem{}, Note: "note"}
+
+	dto := stageOrderDto{}
+	err := mapper.Map(&dto, src)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"convert", "inspect", "decode", "finalize"}, steps)
+
+	// without conversion functions, the price can't be mapped to a string
+	steps = nil
+	err = mapper.Map(&dto, src, WithSkipStages(ConvertStage))
+	assert.Equal(t, NoValidMappingError{ToType: stringRfType, FromType: reflect.TypeOf(stageCents{})}, err)
+
+	steps = nil
+	dto = stageOrderDto{}
+	err = mapper.Map(&dto, src, WithSkipStages(DecodeStage|InspectStage|FinalizeStage))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"convert"}, steps)
+	assert.Equal(t, "converted", dto.Item.Price)
+	assert.Equal(t, "note", dto.Note)
+}
+
+// Values left untouched by skipped sources are inspected only if enabled
+func TestInspectSkipped(t *testing.T) {
+	var inspected []FieldInfo
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(c stageCents) string {
+		return fmt.Sprint(c.Value)
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto, field FieldInfo) {
+		inspected = append(inspected, field)
+	})
+	mapper.AddInspectFunc(func(dto *stageItemDto, src *stageItem, field FieldInfo) {
+		inspected = append(inspected, field)
+	})
+
+	dto := stageOrderDto{Item: stageItemDto{Price: "kept"}}
+	err := mapper.Map(&dto, stageOrder{Items: []*stageItem{{}}})
+	assert.Nil(t, err)
+	assert.Equal(t, "kept", dto.Item.Price)
+	assert.Equal(t, []string{"Items[0]", "Items[0]"}, fieldPaths(inspected))
+
+	// skipped values are marked
+	inspected = nil
+	err = mapper.Map(&dto, stageOrder{}, WithInspectSkipped(true))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Item", "Item"}, fieldPaths(inspected))
+	assert.True(t, inspected[0].Skipped)
+
+	// zeroed values are not skipped
+	inspected = nil
+	err = mapper.Map(&dto, stageOrder{}, WithNilPolicy(ZeroNil))
+	assert.Nil(t, err)
+	assert.Equal(t, stageItemDto{}, dto.Item)
+	assert.Equal(t, []string{"Item", "Item"}, fieldPaths(inspected))
+	assert.False(t, inspected[0].Skipped)
+}
+
+func fieldPaths(fields []FieldInfo) []string {
+	paths := make([]string, len(fields))
+	for i, field := range fields {
+		paths[i] = field.Path
+	}
+	return paths
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// Plan is a mapping from a source type to a destination type
+type Plan struct {
+	mapper  *Mapper
+	dstType reflect.Type
+	srcType reflect.Type
+}
+
+// AllocEstimate reports allocations of mapping a value
+type AllocEstimate struct {
+	Allocs uint64
+	Bytes  uint64
+}
+
+// Number of measured runs for estimates
+const estimateRuns = 3
+
+// Plan creates a Plan for mapping values of the type of src
+// into values of the type of dst. Pointers are removed (first layer only).
+func (m *Mapper) Plan(dst, src interface{}) *Plan {
+	return &Plan{
+		mapper:  m,
+		dstType: reflectValueRemovePtr(dst).Type(),
+		srcType: reflectValueRemovePtr(src).Type(),
+	}
+}
+
+// Map transfers values from src to dst
+// Fails with a NoValidMappingError if they are not of the plan types
+func (p *Plan) Map(dst, src interface{}, opts ...Option) error {
+	if err := p.checkTypes(reflectValueRemovePtr(dst).Type(), reflectValueRemovePtr(src).Type()); err != nil {
+		return err
+	}
+	return p.mapper.Map(dst, src, opts...)
+}
+
+// Check if types match the plan
+func (p *Plan) checkTypes(dstType, srcType reflect.Type) error {
+	if dstType != p.dstType || srcType != p.srcType {
+		return NoValidMappingError{ToType: dstType, FromType: srcType}
+	}
+	return nil
+}
+
+// EstimateAllocs maps srcSample into a new destination value and reports
+// the number of allocations and allocated bytes, including the overhead of reflection.
+// Mapping runs like a Map call, with finalizers and metrics, and its errors are returned.
+//
+// The estimate is taken from the process-wide runtime.MemStats, so allocations of other
+// goroutines are counted as well. Mapping runs several times and the lowest values are
+// reported to reduce their effect, but estimates are unreliable under concurrency.
+func (p *Plan) EstimateAllocs(srcSample interface{}) (AllocEstimate, error) {
+	srcRv := reflectValueRemovePtr(srcSample)
+	if err := p.checkTypes(p.dstType, srcRv.Type()); err != nil {
+		return AllocEstimate{}, err
+	}
+
+	// warm up caches
+	if err := p.mapSample(srcRv, nil, nil); err != nil {
+		return AllocEstimate{}, err
+	}
+
+	var estimate AllocEstimate
+	var before, after runtime.MemStats
+	for i := 0; i < estimateRuns; i++ {
+		if err := p.mapSample(srcRv, &before, &after); err != nil {
+			return AllocEstimate{}, err
+		}
+		run := AllocEstimate{
+			Allocs: after.Mallocs - before.Mallocs,
+			Bytes:  after.TotalAlloc - before.TotalAlloc,
+		}
+		if i == 0 || run.Allocs < estimate.Allocs {
+			estimate.Allocs = run.Allocs
+		}
+		if i == 0 || run.Bytes < estimate.Bytes {
+			estimate.Bytes = run.Bytes
+		}
+	}
+	return estimate, nil
+}
+
+// Map a sample into a new destination value like a Map call,
+// reading memory statistics right before and after if given
+func (p *Plan) mapSample(srcRv reflect.Value, before, after *runtime.MemStats) error {
+	dstRv := reflect.New(p.dstType).Elem()
+	mp := p.mapper.newMapping()
+	if before != nil {
+		runtime.ReadMemStats(before)
+	}
+	err := mp.track(dstRv, srcRv, func() error {
+		return mp.mapValue(dstRv, srcRv)
+	})
+	if after != nil {
+		runtime.ReadMemStats(after)
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Plans map only values of their types
+func TestPlanMap(t *testing.T) {
+	var out benchCart
+	m := Mapper{}
+	plan := m.Plan(&out, ShoppingCart{})
+
+	err := plan.Map(&out, ShoppingCart{Products: commonProducts})
+	assert.Nil(t, err)
+	assert.Equal(t, len(commonProducts), len(out.Products))
+
+	err = plan.Map(&out, cartByCountries)
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+}
+
+// Allocation estimates grow with the source size, elements are mapped without allocations each
+func TestPlanEstimateAllocs(t *testing.T) {
+	m := Mapper{}
+	plan := m.Plan(benchCart{}, ShoppingCart{})
+
+	small, err := plan.EstimateAllocs(benchMakeTestCart(10))
+	assert.Nil(t, err)
+	large, err := plan.EstimateAllocs(benchMakeTestCart(1000))
+	assert.Nil(t, err)
+
+	assert.Greater(t, small.Allocs, uint64(0))
+	assert.Less(t, large.Allocs, uint64(1000))
+	assert.Greater(t, large.Bytes, small.Bytes)
+
+	_, err = plan.EstimateAllocs(commonProducts[0])
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+}
+
+// Allocation estimates fail with errors of the sample mapping, which runs finalizers
+func TestPlanEstimateAllocsErrors(t *testing.T) {
+	m := Mapper{}
+	finalized := 0
+	m.AddFinalizer(func(cart *benchCart) error {
+		finalized++
+		if len(cart.Products) == 0 {
+			return errors.New("empty cart")
+		}
+		return nil
+	})
+	plan := m.Plan(benchCart{}, ShoppingCart{})
+
+	_, err := plan.EstimateAllocs(benchMakeTestCart(10))
+	assert.Nil(t, err)
+	assert.Equal(t, 1+estimateRuns, finalized)
+
+	_, err = plan.EstimateAllocs(ShoppingCart{})
+	assert.EqualError(t, err, "empty cart")
+}
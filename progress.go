@@ -0,0 +1,52 @@
+package dto
+
+import "context"
+
+// WithContext sets a context that aborts mapping when it is done,
+// returning the error of the context. Usually passed to a single Map call.
+//
+// Cancellation is checked before every slice, array and map element,
+// so long running calls on large collections stop early.
+// The context also provides values for fields tagged with inject.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithProgress sets a function that is called every n visited slice, array and map elements
+// with the number of elements visited so far, nested ones included.
+// Useful to monitor large batch mappings. Usually passed to a single Map call.
+func WithProgress(n int, fn func(elements int)) Option {
+	return func(o *options) {
+		o.progressEvery = n
+		o.progress = fn
+	}
+}
+
+// Check for cancellation and report progress if new elements were visited
+func (m *mapping) step() error {
+	if m.elements == m.stepped {
+		return nil
+	}
+	m.stepped = m.elements
+
+	if m.opts.ctx != nil {
+		select {
+		case <-m.opts.ctx.Done():
+			return m.opts.ctx.Err()
+		default:
+		}
+	}
+
+	if m.opts.progress != nil && m.opts.progressEvery > 0 {
+		if m.nextProgress == 0 {
+			m.nextProgress = m.opts.progressEvery
+		}
+		if m.elements >= m.nextProgress {
+			m.opts.progress(m.elements)
+			m.nextProgress = (m.elements/m.opts.progressEvery + 1) * m.opts.progressEvery
+		}
+	}
+	return nil
+}
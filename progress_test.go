@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Progress is reported every n elements
+func TestProgress(t *testing.T) {
+	from := make([]int, 25)
+	var reports []int
+
+	var out []int64
+	err := Map(&out, from, WithProgress(10, func(elements int) {
+		reports = append(reports, elements)
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 20}, reports)
+
+	// nested elements are counted
+	reports = nil
+	var nestedOut [][]int64
+	err = Map(&nestedOut, [][]int{make([]int, 4), make([]int, 4)}, WithProgress(5, func(elements int) {
+		reports = append(reports, elements)
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{5, 10}, reports)
+}
+
+// Mapping is aborted when the context is done
+func TestContextCancel(t *testing.T) {
+	from := make([]int, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var out []int64
+	err := Map(&out, from, WithContext(ctx), WithProgress(10, func(elements int) {
+		if elements == 50 {
+			cancel()
+		}
+	}))
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	err = Map(&out, from, WithContext(context.Background()))
+	assert.Nil(t, err)
+	assert.Len(t, out, 100)
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+var rawMessageRfType = reflect.TypeOf(json.RawMessage(nil))
+
+// Check if a json tag applies to a type pair, i.e. either type is a json.RawMessage
+func isJSONPair(dstType, srcType reflect.Type) bool {
+	return derefType(dstType) == rawMessageRfType || derefType(srcType) == rawMessageRfType
+}
+
+// Map a json.RawMessage by copying its bytes, so the source buffer is not shared
+// Returns false if the values are not raw messages
+func copyRawMessage(dstRv, srcRv reflect.Value) bool {
+	if dstRv.Type() != rawMessageRfType || srcRv.Type() != rawMessageRfType {
+		return false
+	}
+	if srcRv.IsNil() {
+		dstRv.Set(srcRv)
+	} else {
+		dstRv.SetBytes(append([]byte{}, srcRv.Bytes()...))
+	}
+	return true
+}
+
+// Map values of a field with the json tag. Values are marshalled into json.RawMessage
+// destinations and json.RawMessage sources are unmarshalled into new values.
+func (m *mapping) mapJSON(dstRv, srcRv reflect.Value) error {
+	switch {
+	case derefType(dstRv.Type()) == rawMessageRfType && derefType(srcRv.Type()) != rawMessageRfType:
+		raw, err := json.Marshal(srcRv.Interface())
+		if err != nil {
+			return ConversionError{Path: m.pathString(), ToType: dstRv.Type(), FromType: srcRv.Type(), Err: err}
+		}
+		return m.mapValue(dstRv, reflect.ValueOf(json.RawMessage(raw)))
+	case srcRv.Kind() == reflect.Ptr:
+		if srcRv.IsNil() {
+			return m.mapNil(dstRv, srcRv)
+		}
+		return m.mapJSON(dstRv, srcRv.Elem())
+	case srcRv.Type() == rawMessageRfType && derefType(dstRv.Type()) != rawMessageRfType:
+		if srcRv.Len() == 0 {
+			return nil
+		}
+		out := reflect.New(dstRv.Type())
+		if err := json.Unmarshal(srcRv.Bytes(), out.Interface()); err != nil {
+			return ConversionError{Path: m.pathString(), ToType: dstRv.Type(), FromType: srcRv.Type(), Err: err}
+		}
+		dstRv.Set(out.Elem())
+		return nil
+	}
+	return m.mapValue(dstRv, srcRv)
+}
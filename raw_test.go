@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Raw messages are copied as a whole
+func TestRawMessageCopy(t *testing.T) {
+	type EventDto struct {
+		Payload json.RawMessage
+	}
+	from := EventDto{Payload: json.RawMessage(`{"id":1}`)}
+
+	var out struct {
+		Payload json.RawMessage
+		Extra   json.RawMessage
+	}
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"id":1}`, string(out.Payload))
+	from.Payload[0] = '['
+	assert.Equal(t, byte('{'), out.Payload[0])
+}
+
+// Fields with the json tag are marshalled and unmarshalled
+func TestRawMessageJSONTag(t *testing.T) {
+	type Settings struct {
+		Theme string `json:"theme"`
+	}
+	type User struct {
+		Name     string
+		Settings json.RawMessage
+	}
+	type UserDto struct {
+		Name     string
+		Settings *Settings `dto:"json"`
+	}
+
+	var out UserDto
+	err := Map(&out, User{Name: "Alice", Settings: json.RawMessage(`{"theme":"dark"}`)})
+	assert.Nil(t, err)
+	assert.Equal(t, &Settings{Theme: "dark"}, out.Settings)
+
+	var back struct {
+		Settings json.RawMessage `dto:"json"`
+	}
+	err = Map(&back, out)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"theme":"dark"}`, string(back.Settings))
+
+	err = Map(&out, User{Settings: json.RawMessage(`{"theme":1}`)})
+	assert.ErrorAs(t, err, &ConversionError{})
+}
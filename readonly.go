@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Caches of whether struct types contain readonly or ignore_out fields
+var readonlyCache, ignoreOutCache sync.Map
+
+// Check if a struct type or its nested structs have fields with the readonly tag.
+// Pointers, slices and maps are not followed, as their values are mapped separately.
+func hasReadonlyFields(rfType reflect.Type) bool {
+	return hasTaggedFields(rfType, &readonlyCache, func(tags fieldTags) bool { return tags.readonly })
+}
+
+// Check if a struct type or its nested structs have fields with the ignore_out tag
+func hasIgnoreOutFields(rfType reflect.Type) bool {
+	return hasTaggedFields(rfType, &ignoreOutCache, func(tags fieldTags) bool { return tags.ignoreOut })
+}
+
+// Check if a struct type or its nested structs have fields with matching tags, cached
+func hasTaggedFields(rfType reflect.Type, cache *sync.Map, match func(fieldTags) bool) bool {
+	if rfType.Kind() != reflect.Struct {
+		return false
+	}
+	if ok, found := cache.Load(rfType); found {
+		return ok.(bool)
+	}
+	ok := false
+	for i := 0; i < rfType.NumField() && !ok; i++ {
+		field := rfType.Field(i)
+		ok = match(parseTags(field.Tag.Get(structTag))) || hasTaggedFields(field.Type, cache, match)
+	}
+	cache.Store(rfType, ok)
+	return ok
+}
+
+// Reset a destination struct to its zero value, except for readonly fields
+func resetStruct(dstRv reflect.Value) {
+	if !hasReadonlyFields(dstRv.Type()) {
+		dstRv.Set(reflect.Zero(dstRv.Type()))
+		return
+	}
+	for i := 0; i < dstRv.NumField(); i++ {
+		fieldType := dstRv.Type().Field(i)
+		if parseTags(fieldType.Tag.Get(structTag)).readonly {
+			continue
+		}
+		field := dstRv.Field(i)
+		if fieldType.PkgPath != "" {
+			field = exposeField(field)
+		}
+		if hasReadonlyFields(fieldType.Type) {
+			resetStruct(field)
+		} else {
+			field.Set(reflect.Zero(fieldType.Type))
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Readonly fields are never overwritten
+func TestReadonlyFields(t *testing.T) {
+	type Audit struct {
+		CreatedAt time.Time `dto:"readonly"`
+		UpdatedAt time.Time
+	}
+	type User struct {
+		ID    int `dto:"readonly"`
+		Name  string
+		Audit Audit
+	}
+	type UserRequest struct {
+		ID    int
+		Name  string
+		Audit Audit
+	}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+	request := UserRequest{ID: 99, Name: "Bob", Audit: Audit{CreatedAt: updated, UpdatedAt: updated}}
+
+	for _, policy := range []UpdatePolicy{MergeExisting, ReplaceExisting} {
+		user := User{ID: 1, Name: "Alice", Audit: Audit{CreatedAt: created}}
+		err := Map(&user, request, WithUpdatePolicy(policy))
+		assert.Nil(t, err)
+		assert.Equal(t, User{ID: 1, Name: "Bob", Audit: Audit{CreatedAt: created, UpdatedAt: updated}}, user)
+	}
+
+	// also for values of the same type
+	user := User{ID: 1, Audit: Audit{CreatedAt: created}}
+	err := Map(&user, User{ID: 2, Name: "Bob", Audit: Audit{CreatedAt: updated}})
+	assert.Nil(t, err)
+	assert.Equal(t, User{ID: 1, Name: "Bob", Audit: Audit{CreatedAt: created}}, user)
+
+	missing, err := NewMapper().CheckCoverage(User{}, UserRequest{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
+
+// Directional ignore tags exclude fields as destinations or as sources only
+func TestDirectionalIgnoreTags(t *testing.T) {
+	type User struct {
+		ID       int `dto:"ignore_in"`
+		Name     string
+		Password string `dto:"ignore_out"`
+		Session  string `dto:"-"`
+	}
+
+	// shared struct in both directions
+	user := User{ID: 1, Password: "secret", Session: "abc"}
+	err := Map(&user, User{ID: 2, Name: "Bob", Password: "hunter2", Session: "xyz"})
+	assert.Nil(t, err)
+	assert.Equal(t, User{ID: 1, Name: "Bob", Password: "secret", Session: "abc"}, user)
+
+	type UserDto struct {
+		ID       int
+		Name     string
+		Password string
+	}
+	dto := UserDto{}
+	err = Map(&dto, user)
+	assert.Nil(t, err)
+	assert.Equal(t, UserDto{ID: 1, Name: "Bob"}, dto)
+
+	err = Map(&user, UserDto{ID: 3, Name: "Alice", Password: "hunter3"})
+	assert.Nil(t, err)
+	assert.Equal(t, User{ID: 1, Name: "Alice", Password: "hunter3", Session: "abc"}, user)
+
+	// also for maps
+	m := map[string]interface{}{}
+	err = Map(&m, user)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"ID": 1, "Name": "Alice"}, m)
+
+	// neither side reports them
+	mapper := Mapper{}
+	diff, err := mapper.DiffFields(UserDto{}, User{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{DestOnly: []string{"Password"}}, diff)
+}
@@ -0,0 +1,185 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Registered functions of a Mapper
+//
+// Registries are never modified after being stored in a Mapper,
+// registration functions store updated copies instead. This way running
+// Map calls never race with late registrations.
+type registry struct {
+	// linear search might be faster than nested maps
+	convFunc   map[reflect.Type]map[reflect.Type]convertFuncClosure
+	baseFunc   map[reflect.Kind]map[reflect.Type]convertFuncClosure
+	scopeFunc  map[string]map[reflect.Type]map[reflect.Type]convertFuncClosure
+	ownerFunc  map[reflect.Type]map[reflect.Type]map[reflect.Type]convertFuncClosure
+	postFunc   map[reflect.Type]map[reflect.Type][]inspectFuncClosure
+	pathFunc   []pathInspectFunc
+	finalizers []finalizer
+	lessFunc   map[reflect.Type]lessFuncClosure
+	filterFunc map[string]filterFuncClosure
+	flagTables map[string][]namedFlag
+	// decode hooks run in order of registration
+	decodeHooks []decodeHookClosure
+	// conversion factories and the functions they created
+	convFactories []ConvFactory
+	factoryFuncs  *sync.Map
+	optionalTypes []OptionalType
+	resultTypes   []ResultType
+	keyFuncs      []func(key string) string
+	selectors     []sourceSelector
+	// replaced as a whole, so it can be shared
+	kindConverters KindConverters
+	nameMatcher    func(dstName, srcName string) bool
+
+	errorTranslator ErrorTranslator
+
+	// type pairs checked by Validate
+	pairs []typePair
+
+	// descriptions for introspection
+	converters []ConverterInfo
+	hooks      []HookInfo
+}
+
+var emptyRegistry = &registry{}
+
+// Registry and options of a Mapper. They are stored together,
+// so Map calls never see functions and options of different configurations.
+type mapperState struct {
+	reg  *registry
+	opts *options
+}
+
+var emptyState = mapperState{reg: emptyRegistry, opts: &options{}}
+
+// Load the current state of the Mapper
+func (m *Mapper) loadState() mapperState {
+	if s, ok := m.state.Load().(*mapperState); ok {
+		return *s
+	}
+	return emptyState
+}
+
+// Load the current registry of the Mapper
+func (m *Mapper) loadRegistry() *registry {
+	return m.loadState().reg
+}
+
+// Apply a change to a copy of the registry and store it
+func (m *Mapper) updateRegistry(fn func(r *registry)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.loadState()
+	r := s.reg.clone()
+	fn(r)
+	m.state.Store(&mapperState{reg: r, opts: s.opts})
+}
+
+// Clone creates an independent Mapper with the functions and options of m.
+// Later registrations and options of either Mapper don't affect the other,
+// so a shared base Mapper can be customized, for example per test.
+func (m *Mapper) Clone() *Mapper {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clone := &Mapper{}
+	// registries and options are never modified after being stored, so they can be shared
+	s := m.loadState()
+	clone.state.Store(&s)
+	return clone
+}
+
+// Copy conversion functions by type pair
+func cloneConvFuncs(from map[reflect.Type]map[reflect.Type]convertFuncClosure) map[reflect.Type]map[reflect.Type]convertFuncClosure {
+	if from == nil {
+		return nil
+	}
+	out := make(map[reflect.Type]map[reflect.Type]convertFuncClosure, len(from))
+	for inType, toMap := range from {
+		out[inType] = make(map[reflect.Type]convertFuncClosure, len(toMap))
+		for outType, fun := range toMap {
+			out[inType][outType] = fun
+		}
+	}
+	return out
+}
+
+// Copy the registry, so it can be modified independently
+func (r *registry) clone() *registry {
+	out := &registry{
+		convFunc:   cloneConvFuncs(r.convFunc),
+		pathFunc:   append([]pathInspectFunc(nil), r.pathFunc...),
+		finalizers: append([]finalizer(nil), r.finalizers...),
+
+		decodeHooks: append([]decodeHookClosure(nil), r.decodeHooks...),
+
+		convFactories: append([]ConvFactory(nil), r.convFactories...),
+		factoryFuncs:  &sync.Map{},
+		optionalTypes: append([]OptionalType(nil), r.optionalTypes...),
+		resultTypes:   append([]ResultType(nil), r.resultTypes...),
+		keyFuncs:      append([]func(string) string(nil), r.keyFuncs...),
+		selectors:     append([]sourceSelector(nil), r.selectors...),
+
+		kindConverters: r.kindConverters,
+		nameMatcher:    r.nameMatcher,
+
+		errorTranslator: r.errorTranslator,
+
+		pairs: append([]typePair(nil), r.pairs...),
+
+		converters: append([]ConverterInfo(nil), r.converters...),
+		hooks:      append([]HookInfo(nil), r.hooks...),
+	}
+	if r.baseFunc != nil {
+		out.baseFunc = make(map[reflect.Kind]map[reflect.Type]convertFuncClosure, len(r.baseFunc))
+		for kind, toMap := range r.baseFunc {
+			out.baseFunc[kind] = make(map[reflect.Type]convertFuncClosure, len(toMap))
+			for outType, fun := range toMap {
+				out.baseFunc[kind][outType] = fun
+			}
+		}
+	}
+	if r.scopeFunc != nil {
+		out.scopeFunc = make(map[string]map[reflect.Type]map[reflect.Type]convertFuncClosure, len(r.scopeFunc))
+		for scope, convFunc := range r.scopeFunc {
+			out.scopeFunc[scope] = cloneConvFuncs(convFunc)
+		}
+	}
+	if r.ownerFunc != nil {
+		out.ownerFunc = make(map[reflect.Type]map[reflect.Type]map[reflect.Type]convertFuncClosure, len(r.ownerFunc))
+		for owner, convFunc := range r.ownerFunc {
+			out.ownerFunc[owner] = cloneConvFuncs(convFunc)
+		}
+	}
+	if r.postFunc != nil {
+		out.postFunc = make(map[reflect.Type]map[reflect.Type][]inspectFuncClosure, len(r.postFunc))
+		for inType, fromMap := range r.postFunc {
+			out.postFunc[inType] = make(map[reflect.Type][]inspectFuncClosure, len(fromMap))
+			for fromType, funcs := range fromMap {
+				out.postFunc[inType][fromType] = append([]inspectFuncClosure(nil), funcs...)
+			}
+		}
+	}
+	if r.lessFunc != nil {
+		out.lessFunc = make(map[reflect.Type]lessFuncClosure, len(r.lessFunc))
+		for elemType, fun := range r.lessFunc {
+			out.lessFunc[elemType] = fun
+		}
+	}
+	if r.flagTables != nil {
+		out.flagTables = make(map[string][]namedFlag, len(r.flagTables))
+		for name, table := range r.flagTables {
+			out.flagTables[name] = table
+		}
+	}
+	if r.filterFunc != nil {
+		out.filterFunc = make(map[string]filterFuncClosure, len(r.filterFunc))
+		for name, fun := range r.filterFunc {
+			out.filterFunc[name] = fun
+		}
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Functions can be registered while the Mapper is in use
+func TestConcurrentRegistration(t *testing.T) {
+	type ProductDto struct {
+		Name  string
+		Price int
+	}
+	m := Mapper{}
+	m.AddConvFunc(func(p float32) int {
+		return int(p * 100)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				var out []ProductDto
+				assert.Nil(t, m.Map(&out, commonProducts))
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		m.AddInspectFunc(func(dto *ProductDto) {})
+		m.AddConvFunc(func(s string) string { return s })
+	}
+	wg.Wait()
+
+	// registrations don't affect previous snapshots
+	before := m.loadRegistry()
+	m.AddConvFunc(func(p float32) int { return 0 })
+	assert.NotSame(t, before, m.loadRegistry())
+	assert.Equal(t, 1, len(before.postFunc))
+}
+
+// Clones don't share later registrations and options
+func TestClone(t *testing.T) {
+	type ProductDto struct {
+		Name  string
+		Price int
+	}
+	base := NewMapper(WithNilPolicy(RejectNil))
+	base.AddConvFunc(func(p float32) int {
+		return int(p)
+	})
+
+	clone := base.Clone()
+	clone.AddConvFunc(func(p float32) int {
+		return int(p * 10)
+	})
+	clone.Configure(WithNilPolicy(SkipNil))
+
+	var out ProductDto
+	assert.Nil(t, base.Map(&out, commonProducts[0]))
+	assert.Equal(t, 9, out.Price)
+	assert.Nil(t, clone.Map(&out, commonProducts[0]))
+	assert.Equal(t, 94, out.Price)
+
+	var outPtr struct{ Prod Product }
+	from := struct{ Prod *Product }{}
+	assert.ErrorAs(t, base.Map(&outPtr, from), &NilValueError{})
+	assert.Nil(t, clone.Map(&outPtr, from))
+}
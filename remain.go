@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Check if a type can hold the remaining source fields of a remain field
+func isRemainType(rfType reflect.Type) bool {
+	return rfType.Kind() == reflect.Map && rfType.Key().Kind() == reflect.String
+}
+
+// Collect the keys of source fields that have a destination field, including fields matched
+// by json names or the name matcher, outermost source fields of from tags and fields checked by present tags
+func (m *mapping) remainCounterparts(toFields structFieldMap, srcType reflect.Type) map[string]bool {
+	matched := make(map[string]bool)
+	for i := 0; i < toFields.len(); i++ {
+		toField := toFields.at(i)
+		key := toField.key
+		if toField.tags.remain {
+			continue
+		}
+		matched[key] = true
+		if !toField.exported {
+			matched[exportedName(key)] = true
+		}
+		if srcKey, ok := m.matchSourceKey(srcType, key, toField.rawTag); ok {
+			matched[srcKey] = true
+		}
+		if toField.tags.from != "" {
+			for _, path := range strings.Split(toField.tags.from, "|") {
+				if outer, found := structFieldInfoPath(srcType, strings.SplitN(path, ".", 2)[0]); found {
+					matched[outer.key] = true
+				}
+			}
+		}
+		if toField.tags.present != "" {
+			matched[toField.tags.present] = true
+		}
+	}
+	return matched
+}
+
+// Map exported source fields without a destination field to entries of a remain field by their keys.
+// Source fields with the to tag have a destination field and ignore_out fields are never mapped.
+func (m *mapping) mapRemain(dstRv, srcRv reflect.Value, toFields, fromFields structFieldMap) error {
+	if !isRemainType(dstRv.Type()) {
+		return TagError{Tag: "remain", Type: dstRv.Type(), Reason: "not a map with string keys"}
+	}
+	matched := m.remainCounterparts(toFields, srcRv.Type())
+	if m.opts.updatePolicy == ReuseExisting && !dstRv.IsNil() {
+		for _, key := range dstRv.MapKeys() {
+			dstRv.SetMapIndex(key, reflect.Value{})
+		}
+	} else {
+		dstRv.Set(reflect.MakeMap(dstRv.Type()))
+	}
+	for i := 0; i < fromFields.len(); i++ {
+		field := fromFields.at(i)
+		name := field.key
+		if !field.exported || matched[name] || field.tags.to != "" || field.tags.ignoreOut ||
+			(field.tags.omitEmpty && isOmittable(field.value)) {
+			continue
+		}
+		key := reflect.ValueOf(name).Convert(dstRv.Type().Key())
+		toValue := reflect.New(dstRv.Type().Elem()).Elem()
+		m.pushKey(key)
+		err := m.mapValue(toValue, field.value)
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		dstRv.SetMapIndex(key, toValue)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type remainAddress struct {
+	City string
+}
+
+type remainUpstream struct {
+	ID       int
+	Name     string
+	Address  remainAddress
+	Region   string
+	Internal string `dto:"ignore_out"`
+	Nickname string `dto:"omitempty"`
+	Labels   []string
+	Extra    string
+	secret   string
+}
+
+type remainDto struct {
+	ID    int
+	Title string                 `dto:"from=Name"`
+	City  string                 `dto:"from=Address.City"`
+	Extra map[string]interface{} `dto:"remain"`
+}
+
+// Source fields without a destination field are collected by the remain field
+func TestRemainTag(t *testing.T) {
+	dto := remainDto{}
+	err := Map(&dto, remainUpstream{
+		ID:       1,
+		Name:     "Gateway",
+		Address:  remainAddress{City: "Berlin"},
+		Region:   "eu",
+		Internal: "hidden",
+		Labels:   []string{"edge"},
+		Extra:    "kept",
+		secret:   "hidden",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, remainDto{ID: 1, Title: "Gateway", City: "Berlin", Extra: map[string]interface{}{
+		"Region": "eu",
+		"Labels": []string{"edge"},
+		"Extra":  "kept",
+	}}, dto)
+
+	// values are mapped to the element type
+	type labelsDto struct {
+		ID     int
+		Name   string
+		Labels map[string][]string `dto:"remain"`
+	}
+	labels := labelsDto{}
+	err = Map(&labels, struct {
+		ID     int
+		Name   string
+		Tags   []string
+		Owners []string
+	}{ID: 1, Tags: []string{"a"}, Owners: []string{"b"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{"Tags": {"a"}, "Owners": {"b"}}, labels.Labels)
+
+	// all source fields count as mapped
+	mapper := Mapper{}
+	diff, err := mapper.DiffFields(remainDto{}, remainUpstream{})
+	assert.Nil(t, err)
+	assert.Equal(t, FieldDiff{}, diff)
+}
+
+// Remain fields have to be maps with string keys
+func TestRemainTagErrors(t *testing.T) {
+	type BadDto struct {
+		ID    int
+		Extra []string `dto:"remain"`
+	}
+	bad := BadDto{}
+	err := Map(&bad, remainUpstream{})
+	badType := reflect.TypeOf([]string{})
+	assert.Equal(t, TagError{Tag: "remain", Type: badType, Reason: "not a map with string keys"}, err)
+
+	mapper := Mapper{}
+	mapper.RegisterPair(BadDto{}, remainUpstream{})
+	err = mapper.Validate()
+	var ce ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.Len(t, ce, 1)
+	assert.Equal(t, TagError{Tag: "remain", Type: badType, Reason: "not a map with string keys"}, ce[0].Err)
+}
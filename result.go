@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ResultEnvelope is implemented by result types like Result[T]{Value, Err},
+// which are mapped by their value. A non-nil error fails mapping with a ResultError.
+type ResultEnvelope interface {
+	Result() (interface{}, error)
+}
+
+// ResultType describes a family of result types that can't implement ResultEnvelope,
+// like results of a library
+type ResultType struct {
+	// Match reports whether a type belongs to the family, for example by SameGeneric
+	Match func(rfType reflect.Type) bool
+	// Unwrap returns the value of a result or its error
+	Unwrap func(result reflect.Value) (reflect.Value, error)
+}
+
+// ResultError wraps the error of a result envelope with the destination path
+type ResultError struct {
+	Path     string
+	FromType reflect.Type
+	Err      error
+}
+
+func (re ResultError) Error() string {
+	if re.Path == "" {
+		return fmt.Sprintf("Result of %v failed: %v", typeName(re.FromType), re.Err)
+	}
+	return fmt.Sprintf("Result of %v at %v failed: %v", typeName(re.FromType), re.Path, re.Err)
+}
+
+func (re ResultError) Unwrap() error {
+	return re.Err
+}
+
+var resultEnvelopeRfType = reflect.TypeOf((*ResultEnvelope)(nil)).Elem()
+
+// AddResultType adds a family of result types
+func (m *Mapper) AddResultType(rt ResultType) {
+	m.updateRegistry(func(r *registry) {
+		r.resultTypes = append(r.resultTypes, rt)
+	})
+}
+
+// Find the unwrap function of a result type
+// Returns nil if the type is not a result
+func (r *registry) resultOf(rfType reflect.Type) func(reflect.Value) (reflect.Value, error) {
+	for _, rt := range r.resultTypes {
+		if rt.Match(rfType) {
+			return rt.Unwrap
+		}
+	}
+	if rfType.Kind() == reflect.Ptr || rfType.Kind() == reflect.Interface ||
+		!reflect.PtrTo(rfType).Implements(resultEnvelopeRfType) {
+		return nil
+	}
+	return func(result reflect.Value) (reflect.Value, error) {
+		method, _ := findMethod(result, "Result")
+		out := method.Call(nil)
+		if err := errorFromReflectValue(out[1]); err != nil {
+			return reflect.Value{}, err
+		}
+		return out[0], nil
+	}
+}
+
+// Map result sources by their values
+// Returns false if the source is not a result
+func (m *mapping) mapResult(dstRv, srcRv reflect.Value) (bool, error) {
+	if dstRv.Type() == srcRv.Type() {
+		return false, nil
+	}
+	unwrap := m.resultOf(srcRv.Type())
+	if unwrap == nil {
+		return false, nil
+	}
+	value, err := unwrap(srcRv)
+	switch {
+	case err != nil:
+		return true, ResultError{Path: m.pathString(), FromType: srcRv.Type(), Err: err}
+	case !value.IsValid():
+		return true, m.mapNil(dstRv, srcRv)
+	}
+	return true, m.mapUnwrapped(dstRv, value)
+}
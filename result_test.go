@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testServiceResult[T any] struct {
+	Value T
+	Err   error
+}
+
+func (tr testServiceResult[T]) Result() (interface{}, error) {
+	return tr.Value, tr.Err
+}
+
+// Result envelopes are mapped by their values
+func TestResultEnvelope(t *testing.T) {
+	type ProductDto struct {
+		Name string
+	}
+
+	var out ProductDto
+	err := Map(&out, testServiceResult[Product]{Value: commonProducts[0]})
+	assert.Nil(t, err)
+	assert.Equal(t, commonProducts[0].Name, out.Name)
+
+	var outs []ProductDto
+	err = Map(&outs, testServiceResult[[]Product]{Value: commonProducts})
+	assert.Nil(t, err)
+	assert.Len(t, outs, len(commonProducts))
+}
+
+// Errors of result envelopes fail mapping
+func TestResultEnvelopeError(t *testing.T) {
+	errNotFound := errors.New("not found")
+	var out struct {
+		Best struct{ Name string }
+	}
+	err := Map(&out, struct {
+		Best testServiceResult[Product]
+	}{Best: testServiceResult[Product]{Err: errNotFound}})
+
+	var re ResultError
+	assert.ErrorAs(t, err, &re)
+	assert.ErrorIs(t, err, errNotFound)
+	assert.Equal(t, "Best", re.Path)
+}
+
+// Registered result types are unwrapped
+func TestAddResultType(t *testing.T) {
+	type Result struct {
+		Data  string
+		Error string
+	}
+	mapper := NewMapper()
+	mapper.AddResultType(ResultType{
+		Match: func(rfType reflect.Type) bool {
+			return rfType == reflect.TypeOf(Result{})
+		},
+		Unwrap: func(result reflect.Value) (reflect.Value, error) {
+			if msg := result.Field(1).String(); msg != "" {
+				return reflect.Value{}, errors.New(msg)
+			}
+			return result.Field(0), nil
+		},
+	})
+
+	var out string
+	err := mapper.Map(&out, Result{Data: "ok"})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", out)
+
+	err = mapper.Map(&out, Result{Error: "failed"})
+	assert.EqualError(t, err, "Result of dto.Result failed: failed")
+}
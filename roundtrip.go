@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CheckRoundTrip maps src into a new value of the type of via and back into a new value
+// of the type of src, and returns paths of fields that don't survive the round trip,
+// like Address.Zip. Tests can use it to prove that a DTO pair is lossless.
+// Only paths equal to or nested in one of fields are reported if any are given.
+// Pointers are removed (first layer only).
+//
+// Structs are compared field by field, other values with their Equal method if they have one,
+// like time.Time, or with reflect.DeepEqual. Unexported fields are not compared.
+//
+// Returns an error if mapping fails in either direction
+func (m *Mapper) CheckRoundTrip(src, via interface{}, fields ...string) ([]string, error) {
+	srcRv := reflectValueRemovePtr(src)
+	viaRv := reflect.New(reflectValueRemovePtr(via).Type())
+	if err := m.Map(viaRv.Interface(), srcRv.Interface()); err != nil {
+		return nil, err
+	}
+	backRv := reflect.New(srcRv.Type())
+	if err := m.Map(backRv.Interface(), viaRv.Elem().Interface()); err != nil {
+		return nil, err
+	}
+	return collectLostFields(srcRv, backRv.Elem(), "", fields, nil), nil
+}
+
+// Check if a path is selected by a list of paths
+func pathSelected(path string, selected []string) bool {
+	if len(selected) == 0 {
+		return true
+	}
+	for _, s := range selected {
+		if path == s || strings.HasPrefix(path, s+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if a struct type has exported fields to compare one by one
+func hasExportedFields(rfType reflect.Type) bool {
+	for _, info := range structFieldInfos(rfType) {
+		if info.exported {
+			return true
+		}
+	}
+	return false
+}
+
+// Collect paths of values that differ between original and restored
+func collectLostFields(original, restored reflect.Value, path string, selected, lost []string) []string {
+	switch {
+	case original.Kind() == reflect.Ptr && !original.IsNil() && !restored.IsNil():
+		return collectLostFields(original.Elem(), restored.Elem(), path, selected, lost)
+	case original.Kind() == reflect.Struct && hasExportedFields(original.Type()):
+		for _, info := range structFieldInfos(original.Type()) {
+			if !info.exported {
+				continue
+			}
+			fieldPath := info.name
+			if path != "" {
+				fieldPath = path + "." + info.name
+			}
+			lost = collectLostFields(original.FieldByIndex(info.index), restored.FieldByIndex(info.index),
+				fieldPath, selected, lost)
+		}
+		return lost
+	}
+	if !pathSelected(path, selected) || valuesEqual(original, restored) {
+		return lost
+	}
+	return append(lost, path)
+}
+
+// Compare two values of the same type with their Equal method or reflect.DeepEqual
+func valuesEqual(a, b reflect.Value) bool {
+	if equal, ok := a.Type().MethodByName("Equal"); ok && equal.Type.NumIn() == 2 && equal.Type.In(1) == a.Type() &&
+		equal.Type.NumOut() == 1 && equal.Type.Out(0).Kind() == reflect.Bool {
+		return a.Method(equal.Index).Call([]reflect.Value{b})[0].Bool()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
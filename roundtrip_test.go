@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Fields that are dropped or changed on the way are reported
+func TestCheckRoundTrip(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  string
+	}
+	type User struct {
+		Name      string
+		Age       int
+		Address   *Address
+		CreatedAt time.Time
+		Tags      []string
+	}
+	type AddressDto struct {
+		City string
+	}
+	type UserDto struct {
+		Name      string
+		Age       int64
+		Address   *AddressDto
+		CreatedAt time.Time
+		Tags      []string
+	}
+	user := User{
+		Name:      "Bob",
+		Age:       42,
+		Address:   &Address{City: "Berlin", Zip: "10115"},
+		CreatedAt: time.Now(),
+		Tags:      []string{"admin"},
+	}
+
+	mapper := Mapper{}
+	lost, err := mapper.CheckRoundTrip(user, UserDto{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Address.Zip"}, lost)
+
+	// only selected fields are checked
+	lost, err = mapper.CheckRoundTrip(&user, &UserDto{}, "Name", "Address.City")
+	assert.Nil(t, err)
+	assert.Empty(t, lost)
+
+	// changed values are lost too
+	mapper.AddConvFunc(func(name string) string { return name + "!" })
+	lost, err = mapper.CheckRoundTrip(user, UserDto{}, "Name", "Tags")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Name"}, lost)
+}
+
+// Mapping errors in either direction are returned
+func TestCheckRoundTripError(t *testing.T) {
+	type User struct {
+		ID string
+	}
+	type UserDto struct {
+		ID int
+	}
+	mapper := Mapper{}
+	mapper.AddConvFunc(func(id int) string { return "" })
+	_, err := mapper.CheckRoundTrip(User{}, UserDto{})
+	assert.NotNil(t, err)
+}
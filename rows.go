@@ -0,0 +1,151 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Rows is a result set of named columns, like *sql.Rows
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// Column of a result set and the destination field it is mapped to
+type rowColumn struct {
+	index int
+	field structFieldInfo
+}
+
+// MapRows maps all remaining rows into dst, which has to be a pointer to a slice of structs
+// or struct pointers. Columns are matched to fields by name, ignoring case and underscores,
+// so first_name is mapped to FirstName. Columns without a field are skipped.
+// Values are mapped from the types returned by the driver, so conversion functions apply.
+//
+// rows are not closed.
+func (m *Mapper) MapRows(dst interface{}, rows Rows, opts ...Option) error {
+	dstRv := reflectValueRemovePtr(dst)
+	rowsRv := reflect.ValueOf(rows)
+	mp := m.newMapping(opts...)
+	return mp.track(dstRv, rowsRv, func() error {
+		elemType := dstRv.Type()
+		if elemType.Kind() == reflect.Slice {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if dstRv.Kind() != reflect.Slice || elemType.Kind() != reflect.Struct {
+			return NoValidMappingError{ToType: dstRv.Type(), FromType: rowsRv.Type()}
+		}
+		return mp.mapRows(dstRv, elemType, rows)
+	})
+}
+
+// MapRows maps all remaining rows into dst
+func MapRows(dst interface{}, rows Rows, opts ...Option) error {
+	m := Mapper{}
+	return m.MapRows(dst, rows, opts...)
+}
+
+// ScanAll maps all remaining rows into a new slice of T, which has to be a struct
+// or a struct pointer, like MapRows. NULL values are handled by the nil policy.
+// m may be nil to use no conversion functions.
+func ScanAll[T any](rows Rows, m *Mapper, opts ...Option) ([]T, error) {
+	if m == nil {
+		m = &Mapper{}
+	}
+	var out []T
+	if err := m.MapRows(&out, rows, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Normalize a column or field name for matching, like first_name to firstname
+func normalizeColumnName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// Match columns to fields of a struct type
+func (m *mapping) rowColumns(columns []string, structType reflect.Type) []rowColumn {
+	fields := make(map[string]structFieldInfo)
+	for _, info := range structFieldInfos(structType) {
+		if !info.exported && !m.opts.unexportedFields {
+			continue
+		}
+		fields[normalizeColumnName(info.key)] = info
+	}
+	var matched []rowColumn
+	for i, column := range columns {
+		if info, ok := fields[normalizeColumnName(column)]; ok {
+			matched = append(matched, rowColumn{index: i, field: info})
+		}
+	}
+	return matched
+}
+
+// Map rows into a slice of structs or struct pointers
+func (m *mapping) mapRows(dstRv reflect.Value, structType reflect.Type, rows Rows) error {
+	names, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	columns := m.rowColumns(names, structType)
+
+	values := make([]interface{}, len(names))
+	scanArgs := make([]interface{}, len(names))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	out := reflect.MakeSlice(dstRv.Type(), 0, 0)
+	for row := 0; rows.Next(); row++ {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return err
+		}
+		elem := reflect.New(structType)
+		m.pushIndex(row)
+		err := m.mapRow(elem.Elem(), columns, values)
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		if dstRv.Type().Elem().Kind() != reflect.Ptr {
+			elem = elem.Elem()
+		}
+		out = reflect.Append(out, elem)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	dstRv.Set(out)
+	return nil
+}
+
+// Map scanned column values into the fields of a struct
+func (m *mapping) mapRow(dstRv reflect.Value, columns []rowColumn, values []interface{}) error {
+	owner := m.owner
+	m.owner = dstRv.Type()
+	defer func() { m.owner = owner }()
+
+	for _, column := range columns {
+		toField := column.field.of(dstRv)
+		if !toField.exported {
+			toField.value = exposeField(toField.value)
+		}
+		m.pushField(toField.name)
+		field := m.field
+		m.field = toField
+		// typed as interface, so nil values are handled by the nil policy
+		err := m.mapValue(toField.value, reflect.ValueOf(&values[column.index]).Elem())
+		m.field = field
+		m.popPath()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
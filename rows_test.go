@@ -0,0 +1,150 @@
+package dto
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// In-memory result set with driver value types
+type testRows struct {
+	columns []string
+	values  [][]interface{}
+	row     int
+	err     error
+}
+
+func (tr *testRows) Columns() ([]string, error) {
+	return tr.columns, nil
+}
+
+func (tr *testRows) Next() bool {
+	tr.row++
+	return tr.row <= len(tr.values)
+}
+
+func (tr *testRows) Scan(dest ...interface{}) error {
+	for i, value := range tr.values[tr.row-1] {
+		*dest[i].(*interface{}) = value
+	}
+	return nil
+}
+
+func (tr *testRows) Err() error {
+	return tr.err
+}
+
+// Rows are mapped into structs by column names
+func TestMapRows(t *testing.T) {
+	type UserDto struct {
+		ID        int
+		FirstName string
+		Email     *string
+		CreatedAt time.Time
+	}
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := &testRows{
+		columns: []string{"id", "first_name", "email", "created_at", "password"},
+		values: [][]interface{}{
+			{int64(1), []byte("Alice"), []byte("alice@mail.com"), created, []byte("secret")},
+			{int64(2), []byte("Bob"), nil, created, []byte("secret")},
+		},
+	}
+
+	var out []UserDto
+	err := MapRows(&out, rows)
+	assert.Nil(t, err)
+	assert.Len(t, out, 2)
+	assert.Equal(t, 1, out[0].ID)
+	assert.Equal(t, "Alice", out[0].FirstName)
+	assert.Equal(t, "alice@mail.com", *out[0].Email)
+	assert.Equal(t, created, out[0].CreatedAt)
+	assert.Nil(t, out[1].Email)
+}
+
+// Conversion functions apply to column values and errors have row paths
+func TestMapRowsConvFuncs(t *testing.T) {
+	type ProductDto struct {
+		Name  string
+		Price *float64
+	}
+	mapper := NewMapper()
+	mapper.AddConvFunc(func(b []byte) (float64, error) {
+		return 0, errors.New("not a price")
+	})
+	rows := &testRows{
+		columns: []string{"name", "price"},
+		values:  [][]interface{}{{"Shirt", float64(20)}, {"Shoes", []byte("N/A")}},
+	}
+
+	var out []*ProductDto
+	err := mapper.MapRows(&out, rows)
+	var ce ConversionError
+	assert.ErrorAs(t, err, &ce)
+	assert.Equal(t, "[1].Price", ce.Path)
+
+	rows = &testRows{columns: []string{"name"}, err: errors.New("connection lost")}
+	err = mapper.MapRows(&out, rows)
+	assert.EqualError(t, err, "connection lost")
+}
+
+// Conversion and inspection functions get the field info of columns
+func TestMapRowsFieldInfo(t *testing.T) {
+	type OrderDto struct {
+		ID     string
+		Status string `dto:"required"`
+	}
+	var converted, inspected []string
+	mapper := NewMapper()
+	mapper.AddConvFunc(func(b []byte, field FieldInfo) string {
+		converted = append(converted, field.Path)
+		return field.Name + ":" + string(b)
+	})
+	mapper.AddInspectFunc(func(dto *string, field FieldInfo) {
+		inspected = append(inspected, field.Name+" "+string(field.Tag))
+	})
+	rows := &testRows{
+		columns: []string{"id", "status"},
+		values:  [][]interface{}{{[]byte("1"), []byte("paid")}},
+	}
+
+	var out []OrderDto
+	err := mapper.MapRows(&out, rows)
+	assert.Nil(t, err)
+	assert.Equal(t, []OrderDto{{ID: "ID:1", Status: "Status:paid"}}, out)
+	assert.Equal(t, []string{"[0].ID", "[0].Status"}, converted)
+	assert.Equal(t, []string{"ID ", `Status dto:"required"`}, inspected)
+}
+
+// Rows are scanned into new slices, NULL values follow the nil policy
+func TestScanAll(t *testing.T) {
+	type UserDto struct {
+		ID    string
+		Email string
+	}
+	mapper := NewMapper()
+	mapper.AddConvFunc(func(id int64) string {
+		return "user-" + strconv.FormatInt(id, 10)
+	})
+	newRows := func() Rows {
+		return &testRows{
+			columns: []string{"id", "email"},
+			values:  [][]interface{}{{int64(1), "alice@mail.com"}, {int64(2), nil}},
+		}
+	}
+
+	users, err := ScanAll[UserDto](newRows(), mapper)
+	assert.Nil(t, err)
+	assert.Equal(t, []UserDto{{ID: "user-1", Email: "alice@mail.com"}, {ID: "user-2"}}, users)
+
+	ptrs, err := ScanAll[*UserDto](newRows(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, ptrs, 2)
+
+	users, err = ScanAll[UserDto](newRows(), mapper, WithNilPolicy(RejectNil))
+	assert.ErrorAs(t, err, &NilValueError{})
+	assert.Nil(t, users)
+}
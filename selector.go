@@ -0,0 +1,75 @@
+package dto
+
+import "reflect"
+
+// Source selector of a destination struct field
+type sourceSelector struct {
+	toType   reflect.Type
+	fromType reflect.Type
+	field    string
+	fun      func(srcRv reflect.Value) string
+}
+
+// AddSourceSelector adds a function that chooses the source field of a destination field
+// at runtime, like preferring MobilePhone over HomePhone when it's set.
+// It applies to the struct type of dst mapped from the struct type taken by f.
+// f takes the source struct and returns the name or dotted path of the source field to use,
+// like in the from tag, or an empty string to leave the destination field untouched.
+// Selectors take precedence over tags.
+//
+//	mapper.AddSourceSelector(ContactDto{}, "Phone", func(c Contact) string {...})
+//
+// Panics if f is not a valid selector or dst has no such field
+func (m *Mapper) AddSourceSelector(dst interface{}, field string, f interface{}) {
+	toType := reflectValueRemovePtr(dst).Type()
+	rt := reflect.TypeOf(f)
+	if rt == nil || rt.Kind() != reflect.Func || rt.NumIn() != 1 || rt.NumOut() != 1 ||
+		rt.In(0).Kind() != reflect.Struct || rt.Out(0).Kind() != reflect.String {
+		panic("Bad source selector")
+	}
+	if _, ok := structFieldInfoPath(toType, field); toType.Kind() != reflect.Struct || !ok {
+		panic("Source selector field not found")
+	}
+	fv := reflect.ValueOf(f)
+	selector := sourceSelector{
+		toType:   toType,
+		fromType: rt.In(0),
+		field:    field,
+		fun: func(srcRv reflect.Value) string {
+			return fv.Call([]reflect.Value{srcRv})[0].String()
+		},
+	}
+	m.updateRegistry(func(r *registry) {
+		for i, prev := range r.selectors {
+			if prev.toType == toType && prev.fromType == selector.fromType && prev.field == field {
+				r.selectors[i] = selector
+				return
+			}
+		}
+		r.selectors = append(r.selectors, selector)
+	})
+}
+
+// Find the source selector of a destination field
+func (r *registry) findSourceSelector(dstType, srcType reflect.Type, field string) (sourceSelector, bool) {
+	for _, selector := range r.selectors {
+		if selector.toType == dstType && selector.fromType == srcType && selector.field == field {
+			return selector, true
+		}
+	}
+	return sourceSelector{}, false
+}
+
+// Run a source selector and find the selected field.
+// Returns false if no field was selected or a pointer on its path is nil.
+func (sel sourceSelector) selectField(srcRv reflect.Value) (structField, bool, error) {
+	path := sel.fun(srcRv)
+	if path == "" {
+		return structField{}, false, nil
+	}
+	if _, ok := structFieldInfoPath(srcRv.Type(), path); !ok {
+		return structField{}, false, FieldNotFoundError{Type: srcRv.Type(), Field: path}
+	}
+	field, ok := findStructFieldPath(srcRv, path)
+	return field, ok, nil
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type selectorContact struct {
+	Name        string
+	HomePhone   string
+	MobilePhone *string
+}
+
+type selectorContactDto struct {
+	Name  string
+	Phone string
+}
+
+func selectPhone(c selectorContact) string {
+	if c.MobilePhone != nil {
+		return "MobilePhone"
+	}
+	return "HomePhone"
+}
+
+// Selectors choose the source field at runtime
+func TestSourceSelector(t *testing.T) {
+	mapper := Mapper{}
+	mapper.AddSourceSelector(selectorContactDto{}, "Phone", selectPhone)
+	mobile := "0170"
+
+	dto := selectorContactDto{}
+	err := mapper.Map(&dto, selectorContact{Name: "Bob", HomePhone: "030", MobilePhone: &mobile})
+	assert.Nil(t, err)
+	assert.Equal(t, selectorContactDto{Name: "Bob", Phone: "0170"}, dto)
+
+	err = mapper.Map(&dto, selectorContact{Name: "Bob", HomePhone: "030"})
+	assert.Nil(t, err)
+	assert.Equal(t, selectorContactDto{Name: "Bob", Phone: "030"}, dto)
+
+	// empty selections leave the field untouched
+	mapper.AddSourceSelector(&selectorContactDto{}, "Name", func(c selectorContact) string { return "" })
+	err = mapper.Map(&dto, selectorContact{Name: "Alice"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob", dto.Name)
+
+	// the destination field counts as populated
+	missing, err := mapper.CheckCoverage(selectorContactDto{}, selectorContact{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
+
+// Selected fields must exist and selectors must be valid
+func TestSourceSelectorErrors(t *testing.T) {
+	mapper := Mapper{}
+	mapper.AddSourceSelector(selectorContactDto{}, "Phone", func(c selectorContact) string { return "Fax" })
+	err := mapper.Map(&selectorContactDto{}, selectorContact{})
+	assert.Equal(t, FieldNotFoundError{Type: reflect.TypeOf(selectorContact{}), Field: "Fax"}, err)
+
+	assert.Panics(t, func() { mapper.AddSourceSelector(selectorContactDto{}, "Fax", selectPhone) })
+	assert.Panics(t, func() { mapper.AddSourceSelector(selectorContactDto{}, "Phone", func(c string) string { return "" }) })
+	assert.Panics(t, func() { mapper.AddSourceSelector(selectorContactDto{}, "Phone", "Name") })
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Cache of convertibility by type pair
+var convertibleCache sync.Map
+
+// Check if a value of srcType can be mapped to dstType with a single conversion.
+//
+// Distinct struct types of identical shape are converted in bulk instead of field by field,
+// unless either type uses dto tags that would be lost this way, like ignore on the destination
+// or ignore_out on the source. Results are cached.
+func canConvert(dstType, srcType reflect.Type) bool {
+	if dstType.Kind() != reflect.Struct {
+		return srcType.ConvertibleTo(dstType)
+	}
+	pair := typePair{dst: dstType, src: srcType}
+	if ok, found := convertibleCache.Load(pair); found {
+		return ok.(bool)
+	}
+	ok := srcType.ConvertibleTo(dstType) && !hasDtoTags(dstType, make(map[reflect.Type]bool)) &&
+		!hasDtoTags(srcType, make(map[reflect.Type]bool))
+	convertibleCache.Store(pair, ok)
+	return ok
+}
+
+// Check if a type or any type it contains has struct fields with dto or copier tags
+func hasDtoTags(rfType reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[rfType] {
+		return false
+	}
+	visited[rfType] = true
+	switch rfType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasDtoTags(rfType.Elem(), visited)
+	case reflect.Map:
+		return hasDtoTags(rfType.Key(), visited) || hasDtoTags(rfType.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < rfType.NumField(); i++ {
+			field := rfType.Field(i)
+			_, hasDto := field.Tag.Lookup(structTag)
+			_, hasCopier := field.Tag.Lookup(copierTag)
+			if hasDto || hasCopier || hasDtoTags(field.Type, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Structs of identical shape are converted in bulk
+func TestIdenticalShape(t *testing.T) {
+	type CartDto struct {
+		Products []Product
+	}
+	from := ShoppingCart{Products: []Product{commonProducts[0]}}
+
+	var out CartDto
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.Same(t, &from.Products[0], &out.Products[0])
+	assert.True(t, canConvert(reflectValueRemovePtr(&out).Type(), reflectValueRemovePtr(from).Type()))
+}
+
+// Structs of identical shape with dto tags are mapped field by field
+func TestIdenticalShapeTags(t *testing.T) {
+	type CartDto struct {
+		Products []Product `dto:"sort=-Price"`
+	}
+	type ProductDto struct {
+		Name    string
+		Country string `dto:"ignore"`
+		Price   float32
+	}
+
+	products := []Product{commonProducts[0], commonProducts[1]}
+	var out CartDto
+	err := Map(&out, struct{ Products []Product }{products})
+	assert.Nil(t, err)
+	assert.Equal(t, []Product{commonProducts[1], commonProducts[0]}, out.Products)
+
+	var outProduct ProductDto
+	err = Map(&outProduct, commonProducts[0])
+	assert.Nil(t, err)
+	assert.Equal(t, ProductDto{Name: commonProducts[0].Name, Price: commonProducts[0].Price}, outProduct)
+}
+
+// Structs of identical shape with dto tags on the source are mapped field by field
+func TestIdenticalShapeSourceTags(t *testing.T) {
+	type Account struct {
+		Name     string
+		Password string `dto:"ignore_out"`
+	}
+	type AccountDto struct {
+		Name     string
+		Password string
+	}
+	var out AccountDto
+	err := Map(&out, Account{Name: "alice", Password: "secret"})
+	assert.Nil(t, err)
+	assert.Equal(t, AccountDto{Name: "alice"}, out)
+}
+
+type shapeProductDto struct {
+	Name    string
+	Country string
+	Price   float32
+}
+
+type shapeTaggedProductDto struct {
+	Name    string `dto:"name=Name"`
+	Country string
+	Price   float32
+}
+
+// Benchmark bulk conversion of identical shapes
+func BenchmarkIdenticalShape(b *testing.B) {
+	benchmarkShape[shapeProductDto](b)
+}
+
+// Benchmark field by field mapping of identical shapes, as the tag prevents bulk conversion
+func BenchmarkIdenticalShapeFields(b *testing.B) {
+	benchmarkShape[shapeTaggedProductDto](b)
+}
+
+func benchmarkShape[T any](b *testing.B) {
+	products := benchMakeTestCart(1000).Products
+	var out []T
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if err := Map(&out, products); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var syncMapRfType = reflect.TypeOf(sync.Map{})
+
+// Check if a type is an atomic container from sync/atomic with a Load() T method
+func isAtomicType(rfType reflect.Type) bool {
+	if rfType.PkgPath() != "sync/atomic" || rfType.Kind() != reflect.Struct {
+		return false
+	}
+	load, ok := reflect.PtrTo(rfType).MethodByName("Load")
+	return ok && load.Type.NumIn() == 1 && load.Type.NumOut() == 1
+}
+
+// UnaddressableSourceError indicates that a sync.Map or an atomic container can't be read,
+// because it is not addressable and copying it would copy its lock
+type UnaddressableSourceError struct {
+	Type reflect.Type
+}
+
+func (use UnaddressableSourceError) Error() string {
+	return fmt.Sprintf("Source of type %v can't be copied, pass it or the struct holding it by pointer like &src",
+		typeName(use.Type))
+}
+
+// Take a snapshot of a sync.Map or an atomic container, so it can be mapped like a plain value.
+// They must be addressable, as copying them would copy their locks.
+// Returns false if the value is neither.
+func loadSnapshot(srcRv reflect.Value) (reflect.Value, bool, error) {
+	isSyncMap := srcRv.Type() == syncMapRfType
+	if !isSyncMap && !isAtomicType(srcRv.Type()) {
+		return reflect.Value{}, false, nil
+	}
+	if !srcRv.CanAddr() {
+		return reflect.Value{}, true, UnaddressableSourceError{Type: srcRv.Type()}
+	}
+	if isSyncMap {
+		snapshot := make(map[interface{}]interface{})
+		srcRv.Addr().Interface().(*sync.Map).Range(func(key, value interface{}) bool {
+			snapshot[key] = value
+			return true
+		})
+		return reflect.ValueOf(snapshot), true, nil
+	}
+	return srcRv.Addr().MethodByName("Load").Call(nil)[0], true, nil
+}
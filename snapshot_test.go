@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sync.Map and atomic.Value sources are mapped by snapshots
+func TestSnapshotSources(t *testing.T) {
+	type State struct {
+		Sessions sync.Map
+		Config   atomic.Value
+		Best     atomic.Value
+	}
+	type ProductDto struct {
+		Name string
+	}
+	type StateDto struct {
+		Sessions map[string]int64
+		Config   map[string]string
+		Best     *ProductDto
+	}
+
+	state := &State{}
+	state.Sessions.Store("alice", 2)
+	state.Sessions.Store("bob", 5)
+	state.Config.Store(map[string]string{"mode": "fast"})
+
+	var out StateDto
+	err := Map(&out, state)
+	assert.Nil(t, err)
+	assert.Equal(t, StateDto{
+		Sessions: map[string]int64{"alice": 2, "bob": 5},
+		Config:   map[string]string{"mode": "fast"},
+	}, out)
+
+	state.Best.Store(&commonProducts[0])
+	err = Map(&out, state)
+	assert.Nil(t, err)
+	assert.Equal(t, &ProductDto{Name: commonProducts[0].Name}, out.Best)
+}
+
+// sync.Map and atomic.Value sources that are not addressable are rejected instead of copied
+func TestSnapshotSourcesByValue(t *testing.T) {
+	type State struct {
+		Sessions sync.Map
+		Count    atomic.Int64
+	}
+	var out struct {
+		Sessions map[string]int64
+	}
+	err := Map(&out, State{})
+	assert.Equal(t, UnaddressableSourceError{Type: reflect.TypeOf(sync.Map{})}, err)
+
+	state := &State{}
+	state.Count.Store(3)
+	var outCount struct {
+		Count int64
+	}
+	err = Map(&outCount, state)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), outCount.Count)
+
+	err = Map(&outCount, struct{ Count atomic.Int64 }{})
+	assert.Equal(t, UnaddressableSourceError{Type: reflect.TypeOf(atomic.Int64{})}, err)
+}
+
+// Interface sources are mapped by their dynamic values
+func TestInterfaceSources(t *testing.T) {
+	from := []interface{}{commonProducts[0], &commonProducts[1], nil}
+	var out []ProductRef
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, []ProductRef{{Product: commonProducts[0]}, {Product: commonProducts[1]}, {}}, out)
+
+	var outPtr *Product
+	err = Map(&outPtr, from[0])
+	assert.Nil(t, err)
+	assert.Equal(t, commonProducts[0], *outPtr)
+}
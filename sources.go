@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldConflict describes a destination field that two sources populate with different values
+type FieldConflict struct {
+	Path string
+	// First and Second are indices of the sources in the order they were passed
+	First, Second           int
+	FirstValue, SecondValue interface{}
+}
+
+// ConflictError indicates that two sources populate a destination field with different values
+type ConflictError struct {
+	FieldConflict
+}
+
+func (ce ConflictError) Error() string {
+	return fmt.Sprintf("Sources %v and %v conflict at %v: %v != %v",
+		ce.First, ce.Second, ce.Path, ce.FirstValue, ce.SecondValue)
+}
+
+// RejectConflict is a conflict handler that fails mapping with a ConflictError
+func RejectConflict(conflict FieldConflict) error {
+	return ConflictError{conflict}
+}
+
+// WithConflictHandler sets a function that is called by MapSources whenever a source
+// populates a destination field with a different value than a previous source.
+// Mapping stops if it returns an error, otherwise the later value is kept.
+// Pass RejectConflict to fail on any conflict.
+func WithConflictHandler(handler func(FieldConflict) error) Option {
+	return func(o *options) {
+		o.conflictHandler = handler
+	}
+}
+
+// MapSources maps all srcs into dst one after another, for assembling a destination
+// from multiple sources. Later sources overwrite fields populated by earlier ones,
+// which can be detected with WithConflictHandler, but zero values don't clear them.
+// Pointers are removed (first layer only).
+func (m *Mapper) MapSources(dst interface{}, srcs []interface{}, opts ...Option) error {
+	dstRv := reflectValueRemovePtr(dst)
+	mp := m.newMapping(opts...)
+	mp.written = make(map[string]int)
+	return mp.track(dstRv, reflect.ValueOf(srcs), func() error {
+		for i, src := range srcs {
+			mp.sourceIndex = i
+			if err := mp.mapValue(dstRv, reflectValueRemovePtr(src)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MapSources maps all srcs into dst one after another
+func MapSources(dst interface{}, srcs []interface{}, opts ...Option) error {
+	m := Mapper{}
+	return m.MapSources(dst, srcs, opts...)
+}
+
+// Check if a destination field is populated as a whole and not merged field by field
+func isLeafField(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return false
+	case reflect.Ptr:
+		return rv.Type().Elem().Kind() != reflect.Struct
+	}
+	return true
+}
+
+// Take a snapshot of a field value for comparison, including the values of pointers
+func fieldSnapshot(rv reflect.Value) interface{} {
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		return fieldSnapshot(rv.Elem())
+	}
+	return rv.Interface()
+}
+
+// Map a struct field with MapSources, remembering which source populated it
+// and reporting conflicts with previous sources
+func (m *mapping) mapSourceField(dst, src structField) error {
+	path := m.pathString()
+	first, written := m.written[path]
+	// zero values don't clear fields populated by previous sources
+	if written && (!src.value.IsValid() || src.value.IsZero()) {
+		return nil
+	}
+
+	before := fieldSnapshot(dst.value)
+	if err := m.mapField(dst, src); err != nil {
+		return err
+	}
+	after := fieldSnapshot(dst.value)
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	if !written {
+		m.written[path] = m.sourceIndex
+		return nil
+	}
+	if m.opts.conflictHandler == nil {
+		return nil
+	}
+	return m.opts.conflictHandler(FieldConflict{
+		Path:        path,
+		First:       first,
+		Second:      m.sourceIndex,
+		FirstValue:  before,
+		SecondValue: after,
+	})
+}
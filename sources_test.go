@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Sources are mapped in order, later ones overwrite earlier ones
+func TestMapSources(t *testing.T) {
+	type Account struct {
+		Name  string
+		Email string
+	}
+	type Contact struct {
+		Email string
+		Phone *string
+	}
+	type Profile struct {
+		Name  string
+		Email string
+		Phone string
+	}
+	phone := "123"
+
+	profile := Profile{}
+	err := MapSources(&profile, []interface{}{
+		Account{Name: "Bob", Email: "bob@mail.com"},
+		&Contact{Email: "bob@work.com", Phone: &phone},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, Profile{Name: "Bob", Email: "bob@work.com", Phone: "123"}, profile)
+}
+
+// Conflicting fields are reported to the handler or rejected
+func TestMapSourcesConflicts(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  *string
+	}
+	type Profile struct {
+		Name    string
+		Address Address
+	}
+	zipA, zipB := "1000", "2000"
+	srcs := []interface{}{
+		Profile{Name: "Bob", Address: Address{City: "Berlin", Zip: &zipA}},
+		Profile{Address: Address{City: "Berlin"}},
+		Profile{Name: "Bob", Address: Address{City: "Paris", Zip: &zipB}},
+	}
+
+	var conflicts []FieldConflict
+	profile := Profile{}
+	err := MapSources(&profile, srcs, WithConflictHandler(func(c FieldConflict) error {
+		conflicts = append(conflicts, c)
+		return nil
+	}))
+	assert.Nil(t, err)
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Path < conflicts[j].Path })
+	assert.Equal(t, "Bob", profile.Name)
+	assert.Equal(t, "Paris", profile.Address.City)
+	assert.Equal(t, []FieldConflict{
+		{Path: "Address.City", First: 0, Second: 2, FirstValue: "Berlin", SecondValue: "Paris"},
+		{Path: "Address.Zip", First: 0, Second: 2, FirstValue: "1000", SecondValue: "2000"},
+	}, conflicts)
+
+	profile = Profile{}
+	err = MapSources(&profile, []interface{}{srcs[0], Profile{Address: Address{City: "Paris"}}},
+		WithConflictHandler(RejectConflict))
+	var ce ConflictError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, "Address.City", ce.Path)
+	assert.Equal(t, "Sources 0 and 1 conflict at Address.City: Berlin != Paris", ce.Error())
+
+	// equal values don't conflict
+	profile = Profile{}
+	err = MapSources(&profile, srcs[:2], WithConflictHandler(RejectConflict))
+	assert.Nil(t, err)
+}
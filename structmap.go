@@ -0,0 +1,58 @@
+package dto
+
+import "reflect"
+
+// Check if a struct can be mapped to a map type by its field names
+func isStructToMap(dstType, srcType reflect.Type) bool {
+	return dstType.Kind() == reflect.Map && dstType.Key().Kind() == reflect.String &&
+		srcType.Kind() == reflect.Struct
+}
+
+// Get the map key of a struct field, set by the key tag
+func (info structFieldInfo) mapKey() string {
+	if info.tags.key != "" {
+		return info.tags.key
+	}
+	return info.key
+}
+
+// Check if a value is empty for the omitempty tag, like with encoding/json.
+// Empty arrays, slices and maps are omitted, even if they are not nil.
+func isOmittable(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Array:
+		return rv.Len() == 0
+	}
+	return isEmptyValue(rv)
+}
+
+// Map exported struct fields to map entries by their keys.
+// Panics if src is not a struct or dst is not a map with string keys
+func (m *mapping) mapStructToMap(dstRv, srcRv reflect.Value) error {
+	infos := structFieldInfos(srcRv.Type())
+	if m.opts.updatePolicy == ReuseExisting && !dstRv.IsNil() {
+		for _, key := range dstRv.MapKeys() {
+			dstRv.SetMapIndex(key, reflect.Value{})
+		}
+	} else {
+		dstRv.Set(reflect.MakeMapWithSize(dstRv.Type(), len(infos)))
+	}
+	for _, info := range infos {
+		value := srcRv.FieldByIndex(info.index)
+		if !info.exported || info.tags.ignoreOut || (info.tags.omitEmpty && isOmittable(value)) {
+			continue
+		}
+		key := reflect.ValueOf(info.mapKey()).Convert(dstRv.Type().Key())
+		toValue := reflect.New(dstRv.Type().Elem()).Elem()
+		m.pushKey(key)
+		err := m.mapValue(toValue, value)
+		m.popPath()
+		if err != nil {
+			return err
+		}
+		dstRv.SetMapIndex(key, toValue)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Structs are mapped to maps by their field names or key tags
+func TestStructToMap(t *testing.T) {
+	type User struct {
+		FirstName string `dto:"key=first_name"`
+		LastName  string `dto:"key=last_name,omitempty"`
+		Age       int    `dto:"omitempty"`
+		Email     *string
+		Password  string `dto:"ignore"`
+		secret    string
+	}
+
+	var out map[string]interface{}
+	err := Map(&out, User{FirstName: "Alice", Age: 30, Password: "1234", secret: "s"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"first_name": "Alice",
+		"Age":        30,
+		"Email":      nil,
+	}, out)
+}
+
+// Field values are mapped to map elements
+func TestStructToMapElems(t *testing.T) {
+	type Key string
+	mapper := NewMapper()
+	mapper.AddConvFunc(func(i int) string { return strconv.Itoa(i) })
+
+	var out map[Key]string
+	err := mapper.Map(&out, struct {
+		Name  string
+		Price int `dto:"key=price"`
+	}{Name: "Shirt", Price: 20})
+	assert.Nil(t, err)
+	assert.Equal(t, map[Key]string{"Name": "Shirt", "price": "20"}, out)
+}
+
+// Destination fields with omitempty keep their values for empty sources
+func TestOmitEmptyDestination(t *testing.T) {
+	type Patch struct {
+		Name  string
+		Age   *int
+		Tags  []string
+		Email string
+	}
+	type User struct {
+		Name  string   `dto:"omitempty"`
+		Age   int      `dto:"omitempty"`
+		Tags  []string `dto:"omitempty"`
+		Email string
+	}
+	user := User{Name: "Bob", Age: 42, Tags: []string{"admin"}, Email: "bob@mail.com"}
+	err := Map(&user, Patch{Name: "Alice", Tags: []string{}})
+	assert.Nil(t, err)
+	assert.Equal(t, User{Name: "Alice", Age: 42, Tags: []string{"admin"}}, user)
+
+	age := 0
+	err = Map(&user, Patch{Age: &age})
+	assert.Nil(t, err)
+	assert.Equal(t, User{Name: "Alice", Age: 0, Tags: []string{"admin"}}, user)
+}
@@ -0,0 +1,472 @@
+package dto
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Parsed dto struct tag
+type fieldTags struct {
+	ignore  bool
+	index   string
+	groupBy string
+	sort    bool
+	sortKey string
+	unique  string
+	filter  []string
+	unwrap  UnwrapPolicy
+	wrap    bool
+	keysOf  string
+	present string
+	inject  string
+	oneOf   []string
+	min     string
+	max     string
+	pattern string
+	// nil if the pattern is invalid
+	patternRe *regexp.Regexp
+	// map key of the field when mapped to a map
+	key string
+	// skip empty values as map entries and as sources of destination fields
+	omitEmpty bool
+	// marshal to or unmarshal from json.RawMessage
+	json bool
+	// never overwritten by mapping, also set by ignore_in
+	readonly bool
+	// never used as a source
+	ignoreOut bool
+	// must have a source and a non-zero value
+	required bool
+	// text template evaluated against the source struct,
+	// nil if the template is invalid
+	template     string
+	templateTmpl *template.Template
+	// flag table of the flags tag and table.name of the flag tag
+	flags string
+	flag  string
+	// value of the default tag for missing or zero sources
+	defaultValue string
+	hasDefault   bool
+	// fixed value of the const or constInt tag
+	constant string
+	isConst  bool
+	constInt bool
+	// name of the counterpart field, if it's named differently
+	name string
+	// dotted paths of the source field, like Address.City,
+	// alternatives are separated by |
+	from string
+	// dotted path of the destination field, like Profile.Bio
+	to string
+	// fields of the struct are promoted like embedded fields
+	squash bool
+	// map of the source fields without a destination field
+	remain bool
+	// fmt format for numbers mapped to strings
+	format string
+	// decimal places of float fields, empty if not rounded
+	precision string
+}
+
+// Struct field value with its parsed tags
+type structField struct {
+	name     string
+	key      string
+	value    reflect.Value
+	tags     fieldTags
+	rawTag   reflect.StructTag
+	exported bool
+}
+
+// Fields of a struct value, resolved lazily from the cached layout of its type
+type structFieldMap struct {
+	rv     reflect.Value
+	layout *structLayout
+}
+
+// Field layout of a struct type, independent of its values
+type structFieldInfo struct {
+	name string
+	// name for matching fields, usually the field name
+	key      string
+	index    []int
+	tags     fieldTags
+	rawTag   reflect.StructTag
+	exported bool
+}
+
+// Cache of field layouts by struct type
+var structInfoCache sync.Map
+
+// Field layout of a struct type by keys, the last field wins for duplicate keys
+type structLayout struct {
+	fields []structFieldInfo
+	byKey  map[string]int
+}
+
+// Cache of field layouts by keys by struct type
+var structLayoutCache sync.Map
+
+// ==================================== Tag parsing ===========================
+
+// Split a tag option into key and value
+func splitTagOption(option string) (string, string) {
+	option = strings.TrimSpace(option)
+	if i := strings.IndexByte(option, '='); i >= 0 {
+		return strings.TrimSpace(option[:i]), strings.TrimSpace(option[i+1:])
+	}
+	return option, ""
+}
+
+// Parse a dto struct tag of comma separated options.
+// Patterns and templates may contain commas, so they take the rest of the tag.
+func parseTags(tag string) fieldTags {
+	var tags fieldTags
+	options := strings.Split(tag, ",")
+	for i, option := range options {
+		key, value := splitTagOption(option)
+		switch key {
+		case "ignore", "-":
+			tags.ignore = true
+		case "ignore_in":
+			// a field that is never a destination is readonly
+			tags.readonly = true
+		case "ignore_out":
+			tags.ignoreOut = true
+		case "index":
+			tags.index = value
+		case "groupby":
+			tags.groupBy = value
+		case "sort":
+			tags.sort = true
+			tags.sortKey = value
+		case "unique":
+			tags.unique = value
+		case "filter":
+			tags.filter = append(tags.filter, value)
+		case "single":
+			tags.unwrap = UnwrapSingle
+		case "first":
+			tags.unwrap = UnwrapFirst
+		case "wrap":
+			tags.wrap = true
+		case "keys":
+			tags.keysOf = value
+		case "present":
+			tags.present = value
+		case "inject":
+			tags.inject = value
+		case "flags":
+			tags.flags = value
+		case "flag":
+			tags.flag = value
+		case "default":
+			tags.defaultValue = value
+			tags.hasDefault = true
+		case "const", "constInt":
+			tags.constant = value
+			tags.isConst = true
+			tags.constInt = key == "constInt"
+		case "key":
+			tags.key = value
+		case "omitempty":
+			tags.omitEmpty = true
+		case "json":
+			tags.json = true
+		case "readonly":
+			tags.readonly = true
+		case "name":
+			tags.name = value
+		case "from":
+			tags.from = value
+		case "to":
+			tags.to = value
+		case "required":
+			tags.required = true
+		case "squash":
+			tags.squash = true
+		case "remain":
+			tags.remain = true
+		case "format":
+			tags.format = value
+		case "precision":
+			tags.precision = value
+		case "oneof":
+			tags.oneOf = strings.Fields(value)
+		case "min":
+			tags.min = value
+		case "max":
+			tags.max = value
+		case "pattern":
+			_, tags.pattern = splitTagOption(strings.Join(options[i:], ","))
+			tags.patternRe, _ = regexp.Compile(tags.pattern)
+			return tags
+		case "template":
+			_, tags.template = splitTagOption(strings.Join(options[i:], ","))
+			tags.templateTmpl, _ = template.New("").Parse(tags.template)
+			return tags
+		}
+	}
+	return tags
+}
+
+// Options of dto struct tags
+var tagOptions = map[string]bool{
+	"ignore": true, "-": true, "ignore_in": true, "ignore_out": true,
+	"index": true, "groupby": true, "sort": true, "unique": true, "filter": true,
+	"single": true, "first": true, "wrap": true, "keys": true, "present": true, "inject": true,
+	"const": true, "constInt": true, "default": true, "template": true, "flags": true, "flag": true,
+	"key": true, "omitempty": true, "json": true, "readonly": true, "name": true, "from": true, "to": true,
+	"squash": true, "remain": true, "format": true, "precision": true, "required": true, "oneof": true, "min": true, "max": true, "pattern": true,
+}
+
+// Find an unknown option of a dto struct tag
+func unknownTagOption(tag string) (string, bool) {
+	for _, option := range strings.Split(tag, ",") {
+		key, _ := splitTagOption(option)
+		if key == "pattern" || key == "template" {
+			break
+		}
+		if key != "" && !tagOptions[key] {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// Check if a field is derived from other fields, injected, constant, composed or defaulted, if it has no source
+func (ft fieldTags) derived() bool {
+	return ft.keysOf != "" || ft.present != "" || ft.hasDefault || ft.overridesSource()
+}
+
+// Check if a field is injected, constant, composed with a template or collects remaining fields,
+// even if it has a source
+func (ft fieldTags) overridesSource() bool {
+	return ft.inject != "" || ft.isConst || ft.template != "" || ft.remain
+}
+
+// ==================================== Field collection ======================
+
+// Collect field layout of a struct type (including anonymous and squashed fields)
+func collectStructFieldInfos(rfType reflect.Type, index []int, infos []structFieldInfo) []structFieldInfo {
+	for i := 0; i < rfType.NumField(); i++ {
+		fieldType := rfType.Field(i)
+		tags := parseTags(fieldType.Tag.Get(structTag))
+		copierName, copierIgnore := parseCopierTag(fieldType.Tag.Get(copierTag))
+		if tags.ignore || copierIgnore {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if (fieldType.Anonymous || tags.squash) && fieldType.Type.Kind() == reflect.Struct {
+			infos = collectStructFieldInfos(fieldType.Type, fieldIndex, infos)
+		} else {
+			key := fieldType.Name
+			switch {
+			case tags.name != "":
+				key = tags.name
+			case copierName != "":
+				key = copierName
+			}
+			infos = append(infos, structFieldInfo{
+				name:     fieldType.Name,
+				key:      key,
+				index:    fieldIndex,
+				tags:     tags,
+				rawTag:   fieldType.Tag,
+				exported: fieldType.PkgPath == "",
+			})
+		}
+	}
+	return infos
+}
+
+// Get field layout of a struct type, cached
+func structFieldInfos(rfType reflect.Type) []structFieldInfo {
+	if infos, ok := structInfoCache.Load(rfType); ok {
+		return infos.([]structFieldInfo)
+	}
+	infos := collectStructFieldInfos(rfType, nil, nil)
+	structInfoCache.Store(rfType, infos)
+	return infos
+}
+
+// Get field layout of a struct type by keys, cached
+func structLayoutOf(rfType reflect.Type) *structLayout {
+	if layout, ok := structLayoutCache.Load(rfType); ok {
+		return layout.(*structLayout)
+	}
+	infos := structFieldInfos(rfType)
+	layout := &structLayout{byKey: make(map[string]int, len(infos))}
+	for _, info := range infos {
+		if i, ok := layout.byKey[info.key]; ok {
+			layout.fields[i] = info
+			continue
+		}
+		layout.byKey[info.key] = len(layout.fields)
+		layout.fields = append(layout.fields, info)
+	}
+	structLayoutCache.Store(rfType, layout)
+	return layout
+}
+
+// Collect all struct fields (including anonymous) into a structFieldMap.
+// Field values are resolved on access, so no memory is allocated per value.
+func collectStructFields(rfValue reflect.Value) structFieldMap {
+	return structFieldMap{rv: rfValue, layout: structLayoutOf(rfValue.Type())}
+}
+
+// Number of fields
+func (sfm structFieldMap) len() int {
+	return len(sfm.layout.fields)
+}
+
+// Get the i-th field
+func (sfm structFieldMap) at(i int) structField {
+	return sfm.layout.fields[i].of(sfm.rv)
+}
+
+// Get the field of a struct value
+func (info *structFieldInfo) of(structRv reflect.Value) structField {
+	return structField{
+		name:     info.name,
+		key:      info.key,
+		value:    structRv.FieldByIndex(info.index),
+		tags:     info.tags,
+		rawTag:   info.rawTag,
+		exported: info.exported,
+	}
+}
+
+// Get a field by its key
+func (sfm structFieldMap) get(key string) (structField, bool) {
+	i, ok := sfm.layout.byKey[key]
+	if !ok {
+		return structField{}, false
+	}
+	return sfm.at(i), true
+}
+
+// Check if there is a field with a key
+func (sfm structFieldMap) has(key string) bool {
+	_, ok := sfm.layout.byKey[key]
+	return ok
+}
+
+// Find a struct field by name, dereferencing pointers.
+// Returns false if the value is not a struct, a nil pointer or has no such field.
+func findStructField(rfValue reflect.Value, name string) (reflect.Value, bool) {
+	for rfValue.Kind() == reflect.Ptr {
+		if rfValue.IsNil() {
+			return reflect.Value{}, false
+		}
+		rfValue = rfValue.Elem()
+	}
+	if rfValue.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	field, ok := collectStructFields(rfValue).get(name)
+	return field.value, ok
+}
+
+// Find the field layout of a struct field by a dotted path of exported field names,
+// through nested structs and pointers to them. The index of the result spans the whole path.
+func structFieldInfoPath(rfType reflect.Type, path string) (structFieldInfo, bool) {
+	var found structFieldInfo
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		if rfType.Kind() == reflect.Ptr {
+			rfType = rfType.Elem()
+		}
+		if rfType.Kind() != reflect.Struct {
+			return structFieldInfo{}, false
+		}
+		ok := false
+		for _, info := range structFieldInfos(rfType) {
+			if info.name == name && info.exported {
+				found, ok = info, true
+				break
+			}
+		}
+		if !ok {
+			return structFieldInfo{}, false
+		}
+		index = append(index, found.index...)
+		rfType = rfType.FieldByIndex(found.index).Type
+	}
+	found.index = index
+	return found, true
+}
+
+// Find a struct field by a dotted path of exported field names.
+// Returns false if there is no such field or a pointer on the way is nil.
+func findStructFieldPath(rfValue reflect.Value, path string) (structField, bool) {
+	info, ok := structFieldInfoPath(rfValue.Type(), path)
+	if !ok {
+		return structField{}, false
+	}
+	value, err := rfValue.FieldByIndexErr(info.index)
+	if err != nil {
+		return structField{}, false
+	}
+	return structField{name: info.name, value: value, tags: info.tags, rawTag: info.rawTag, exported: true}, true
+}
+
+// Find the source field of the from tag. The first alternative that exists and is
+// non-zero is used, or the first that exists if all are zero.
+func findFromField(rfValue reflect.Value, from string) (structField, bool) {
+	var first structField
+	found := false
+	for _, path := range strings.Split(from, "|") {
+		field, ok := findStructFieldPath(rfValue, path)
+		if !ok {
+			continue
+		}
+		if !field.value.IsZero() {
+			return field, true
+		}
+		if !found {
+			first, found = field, true
+		}
+	}
+	return first, found
+}
+
+// Find the field layout of the first existing alternative of the from tag
+func fromFieldInfo(rfType reflect.Type, from string) (structFieldInfo, bool) {
+	for _, path := range strings.Split(from, "|") {
+		if info, ok := structFieldInfoPath(rfType, path); ok {
+			return info, true
+		}
+	}
+	return structFieldInfo{}, false
+}
+
+// Find a struct field by a dotted path of exported field names, like structFieldInfoPath,
+// allocating nil pointers on the way
+func allocStructFieldPath(rfValue reflect.Value, path string) (structField, error) {
+	field := structField{value: rfValue}
+	for _, name := range strings.Split(path, ".") {
+		rfValue = field.value
+		for rfValue.Kind() == reflect.Ptr {
+			if rfValue.IsNil() {
+				rfValue.Set(reflect.New(rfValue.Type().Elem()))
+			}
+			rfValue = rfValue.Elem()
+		}
+		if rfValue.Kind() != reflect.Struct {
+			return structField{}, FieldNotFoundError{Type: rfValue.Type(), Field: name}
+		}
+		fields, ok := collectStructFields(rfValue), false
+		for i := 0; i < fields.len() && !ok; i++ {
+			field = fields.at(i)
+			ok = field.name == name && field.exported
+		}
+		if !ok {
+			return structField{}, FieldNotFoundError{Type: rfValue.Type(), Field: name}
+		}
+	}
+	return field, nil
+}
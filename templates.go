@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// Get the template of a field with the template tag.
+// Fails if the template is invalid or the field is not a string.
+func fieldTemplate(dstType reflect.Type, tags fieldTags) (*template.Template, error) {
+	if derefType(dstType).Kind() != reflect.String {
+		return nil, TagError{Tag: "template", Type: dstType, Reason: "not a string"}
+	}
+	if tags.templateTmpl == nil {
+		_, err := template.New("").Parse(tags.template)
+		return nil, TagError{Tag: "template", Type: dstType, Reason: err.Error()}
+	}
+	return tags.templateTmpl, nil
+}
+
+// Set a destination field to its template evaluated against the source struct.
+// Execution errors are reported as conversion errors at the path of the field.
+func (m *mapping) mapTemplate(dstRv, srcRv reflect.Value, tags fieldTags) error {
+	tmpl, err := fieldTemplate(dstRv.Type(), tags)
+	if err != nil {
+		return err
+	}
+	if !srcRv.IsValid() || !srcRv.CanInterface() {
+		return NoValidMappingError{ToType: dstRv.Type()}
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, srcRv.Interface()); err != nil {
+		return ConversionError{
+			Path:     m.pathString(),
+			ToType:   dstRv.Type(),
+			FromType: srcRv.Type(),
+			Err:      TagError{Tag: "template", Type: srcRv.Type(), Reason: err.Error()},
+		}
+	}
+	return m.mapValue(dstRv, reflect.ValueOf(sb.String()))
+}
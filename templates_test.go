@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Template fields are composed from the source struct
+func TestTemplateTag(t *testing.T) {
+	type Person struct {
+		FirstName string
+		LastName  string
+		Age       int
+	}
+	type PersonDto struct {
+		FullName string  `dto:"template={{.FirstName}} {{.LastName}}"`
+		Summary  *string `dto:"required,template={{.FirstName}}, {{.Age}}"`
+	}
+	dto := PersonDto{}
+	err := Map(&dto, Person{FirstName: "Bob", LastName: "Smith", Age: 42})
+	assert.Nil(t, err)
+	assert.Equal(t, "Bob Smith", dto.FullName)
+	assert.Equal(t, "Bob, 42", *dto.Summary)
+
+	// they are not reported as missing
+	mapper := Mapper{}
+	missing, err := mapper.CheckCoverage(PersonDto{}, Person{})
+	assert.Nil(t, err)
+	assert.Empty(t, missing)
+}
+
+// Invalid templates, fields and missing source fields are reported
+func TestTemplateTagErrors(t *testing.T) {
+	type Person struct {
+		Name string
+	}
+	type BadTemplate struct {
+		Name string `dto:"template={{.Name"`
+	}
+	type BadField struct {
+		Name int `dto:"template={{.Name}}"`
+	}
+	type BadSource struct {
+		Name string `dto:"template={{.Nickname}}"`
+	}
+	for _, dst := range []interface{}{&BadTemplate{}, &BadField{}, &BadSource{}} {
+		err := Map(dst, Person{Name: "Bob"})
+		var te TagError
+		assert.True(t, errors.As(err, &te))
+		assert.Equal(t, "template", te.Tag)
+	}
+
+	mapper := Mapper{}
+	mapper.RegisterPair(BadTemplate{}, Person{})
+	mapper.RegisterPair(BadField{}, Person{})
+	var ce ConfigError
+	assert.True(t, errors.As(mapper.Validate(), &ce))
+	assert.Len(t, ce, 2)
+}
+
+// Execution errors are reported at the path of the field
+func TestTemplateTagExecutionError(t *testing.T) {
+	type Person struct {
+		Name string
+	}
+	type Team struct {
+		Members []Person
+	}
+	type PersonDto struct {
+		Greeting string `dto:"template={{call .Name}}"`
+	}
+	var out struct {
+		Members []PersonDto
+	}
+	err := Map(&out, Team{Members: []Person{{Name: "Bob"}}})
+	var ce ConversionError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, "Members[0].Greeting", ce.Path)
+	var te TagError
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, "template", te.Tag)
+
+	// missing sources don't panic
+	var greeting string
+	tags := fieldTags{template: "{{.Name}}", templateTmpl: template.Must(template.New("").Parse("{{.Name}}"))}
+	m := mapping{}
+	err = m.mapTemplate(reflect.ValueOf(&greeting).Elem(), reflect.Value{}, tags)
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"strings"
+	"time"
+)
+
+// AddTimeConvFuncs adds conversion functions between time.Time, int64 unix seconds
+// and RFC 3339 strings for audit fields, which are named like CreatedAt or UpdatedAt
+// by convention. Destination fields whose names end with At are converted, or only
+// the given field names if any. Other values are mapped as if the functions didn't exist.
+// Pointers like *time.Time are dereferenced and allocated as usual.
+//
+// Zero times are mapped to 0 and empty strings and vice versa, so unset fields stay unset.
+// Likewise, 0 and empty strings are mapped to nil *time.Time values.
+func (m *Mapper) AddTimeConvFuncs(fields ...string) {
+	isTimeField := func(field FieldInfo) bool {
+		if len(fields) == 0 {
+			return len(field.Name) > 2 && strings.HasSuffix(field.Name, "At")
+		}
+		for _, name := range fields {
+			if field.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	parseTime := func(s string) (time.Time, error) {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return t, ParseError{Value: s, Type: timeRfType}
+		}
+		return t, nil
+	}
+
+	// unix seconds
+	m.AddConvFunc(func(t time.Time, field FieldInfo) (int64, error) {
+		if !isTimeField(field) {
+			return 0, errNotConverted
+		}
+		if t.IsZero() {
+			return 0, nil
+		}
+		return t.Unix(), nil
+	})
+	m.AddConvFunc(func(sec int64, field FieldInfo) (time.Time, error) {
+		if !isTimeField(field) {
+			return time.Time{}, errNotConverted
+		}
+		if sec == 0 {
+			return time.Time{}, nil
+		}
+		return time.Unix(sec, 0).UTC(), nil
+	})
+	m.AddConvFunc(func(sec int64, field FieldInfo) (*time.Time, error) {
+		if !isTimeField(field) {
+			return nil, errNotConverted
+		}
+		if sec == 0 {
+			return nil, nil
+		}
+		t := time.Unix(sec, 0).UTC()
+		return &t, nil
+	})
+
+	// strings
+	m.AddConvFunc(func(t time.Time, field FieldInfo) (string, error) {
+		if !isTimeField(field) {
+			return "", errNotConverted
+		}
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(time.RFC3339Nano), nil
+	})
+	m.AddConvFunc(func(s string, field FieldInfo) (time.Time, error) {
+		if !isTimeField(field) {
+			return time.Time{}, errNotConverted
+		}
+		if s == "" {
+			return time.Time{}, nil
+		}
+		return parseTime(s)
+	})
+	m.AddConvFunc(func(s string, field FieldInfo) (*time.Time, error) {
+		if !isTimeField(field) {
+			return nil, errNotConverted
+		}
+		if s == "" {
+			return nil, nil
+		}
+		t, err := parseTime(s)
+		return &t, err
+	})
+}
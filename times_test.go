@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Audit fields are mapped between times, unix seconds and strings
+func TestTimeConvFuncs(t *testing.T) {
+	type Entity struct {
+		CreatedAt time.Time
+		UpdatedAt *time.Time
+		DeletedAt *time.Time
+	}
+	type EntityDto struct {
+		CreatedAt int64
+		UpdatedAt string
+		DeletedAt *string
+	}
+	m := Mapper{}
+	m.AddTimeConvFuncs()
+
+	created := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	updated := created.Add(90 * time.Minute)
+	from := Entity{CreatedAt: created, UpdatedAt: &updated}
+
+	var out EntityDto
+	err := m.Map(&out, from)
+	assert.Nil(t, err)
+	assert.Equal(t, EntityDto{CreatedAt: created.Unix(), UpdatedAt: "2022-03-01T13:30:00Z"}, out)
+
+	var back Entity
+	err = m.Map(&back, out)
+	assert.Nil(t, err)
+	assert.Equal(t, from, back)
+
+	out.UpdatedAt = "yesterday"
+	err = m.Map(&back, out)
+	assert.ErrorAs(t, err, &ParseError{})
+}
+
+// Only fields named by the convention are converted
+func TestTimeConvFuncsConvention(t *testing.T) {
+	type Event struct {
+		CreatedAt time.Time
+		Start     time.Time
+		Count     int64
+	}
+	created := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	from := Event{CreatedAt: created, Start: created, Count: 3}
+
+	m := Mapper{}
+	m.AddTimeConvFuncs()
+	{
+		var out struct {
+			CreatedAt string
+			Count     int64
+		}
+		err := m.Map(&out, from)
+		assert.Nil(t, err)
+		assert.Equal(t, "2022-03-01T12:00:00Z", out.CreatedAt)
+		assert.Equal(t, int64(3), out.Count)
+	}
+	{
+		var out struct {
+			Start string
+		}
+		err := m.Map(&out, from)
+		assert.ErrorAs(t, err, &NoValidMappingError{})
+	}
+
+	// given field names replace the convention
+	m = Mapper{}
+	m.AddTimeConvFuncs("Start")
+	{
+		var out struct {
+			CreatedAt int64
+			Start     int64
+		}
+		err := m.Map(&out, from)
+		assert.ErrorAs(t, err, &NoValidMappingError{})
+
+		var start struct {
+			Start int64
+		}
+		err = m.Map(&start, from)
+		assert.Nil(t, err)
+		assert.Equal(t, created.Unix(), start.Start)
+	}
+}
+
+// Other fields are mapped as if the functions didn't exist
+func TestTimeConvFuncsFallThrough(t *testing.T) {
+	type Event struct {
+		CreatedAt string
+		Start     string
+		DeletedAt string
+	}
+	type EventDto struct {
+		CreatedAt time.Time
+		Start     time.Time
+		DeletedAt *time.Time
+	}
+	m := Mapper{}
+	m.AddConvFuncForUnderlying(func(s string) (time.Time, error) {
+		return time.Parse("2006-01-02", s)
+	})
+	m.AddTimeConvFuncs()
+
+	var out EventDto
+	err := m.Map(&out, Event{CreatedAt: "2022-03-01T12:00:00Z", Start: "2022-03-02"})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC), out.CreatedAt)
+	assert.Equal(t, time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC), out.Start)
+	assert.Nil(t, out.DeletedAt)
+
+	err = m.Map(&out, Event{Start: "2022-03-02", DeletedAt: "2022-03-03T00:00:00Z"})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2022, 3, 3, 0, 0, 0, 0, time.UTC), *out.DeletedAt)
+
+	// failures of other fields are not conversion errors
+	var start struct{ Start int64 }
+	err = m.Map(&start, EventDto{})
+	assert.ErrorAs(t, err, &NoValidMappingError{})
+	assert.False(t, errors.As(err, &ConversionError{}))
+}
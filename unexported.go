@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Make a value obtained from an unexported field of an addressable struct settable.
+// The returned value points to the same memory, so the garbage collector keeps track of it.
+func exposeField(rv reflect.Value) reflect.Value {
+	return reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem()
+}
+
+// Get the exported form of a field name, like id to Id
+func exportedName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// Get the unexported form of a field name, like Id to id
+func unexportedName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// Find the source field of a destination field.
+// Unexported destination fields are also matched by their exported name,
+// exported ones by their unexported name if unexported source fields are enabled.
+func (m *mapping) findSourceField(fromFields structFieldMap, name string, exported bool) (structField, bool) {
+	field, ok := fromFields.get(name)
+	switch {
+	case !ok && !exported:
+		field, ok = fromFields.get(exportedName(name))
+	case !ok && m.opts.unexportedSources:
+		field, ok = fromFields.get(unexportedName(name))
+	}
+	if !ok || field.exported {
+		return field, ok
+	}
+	if !m.opts.unexportedSources {
+		return structField{}, false
+	}
+	field.value, ok = copyBasicValue(field.value)
+	return field, ok
+}
+
+// Copy a value of a basic kind, so that it is no longer marked as obtained
+// from an unexported field. Other kinds could share memory and are not copied.
+func copyBasicValue(rv reflect.Value) (reflect.Value, bool) {
+	out := reflect.New(rv.Type()).Elem()
+	switch rv.Kind() {
+	case reflect.Bool:
+		out.SetBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		out.SetInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		out.SetUint(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		out.SetFloat(rv.Float())
+	case reflect.Complex64, reflect.Complex128:
+		out.SetComplex(rv.Complex())
+	case reflect.String:
+		out.SetString(rv.String())
+	default:
+		return reflect.Value{}, false
+	}
+	return out, true
+}
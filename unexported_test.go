@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type legacyProduct struct {
+	id    int
+	name  string
+	tags  []string
+	Price float32
+}
+
+type productDto struct {
+	Id    int
+	Name  string
+	Tags  []string
+	Price float32
+}
+
+// Unexported destination fields are skipped by default
+func TestUnexportedFieldsSkipped(t *testing.T) {
+	var out legacyProduct
+	err := Map(&out, productDto{Id: 1, Name: "Shirt", Price: 9.4})
+	assert.Nil(t, err)
+	assert.Equal(t, legacyProduct{Price: 9.4}, out)
+
+	var outUnexported struct {
+		name  string
+		Price float32
+	}
+	err = Map(&outUnexported, struct{ name string }{"Shirt"})
+	assert.Nil(t, err)
+	assert.Zero(t, outUnexported)
+}
+
+// Unexported destination fields are set with WithUnexportedFields
+func TestUnexportedFields(t *testing.T) {
+	from := []productDto{{1, "Shirt", []string{"summer"}, 9.4}, {2, "Shoes", nil, 17.3}}
+
+	var out []legacyProduct
+	err := Map(&out, from, WithUnexportedFields(true))
+	assert.Nil(t, err)
+
+	runtime.GC()
+	assert.Equal(t, []legacyProduct{{1, "Shirt", []string{"summer"}, 9.4}, {2, "Shoes", nil, 17.3}}, out)
+}
+
+type userID struct {
+	id     int64
+	region string
+	parent *userID
+}
+
+// Unexported source fields of basic kinds are read with WithUnexportedSources
+func TestUnexportedSources(t *testing.T) {
+	type UserIDDto struct {
+		Id     int64
+		Region string
+		Parent *UserIDDto
+	}
+	from := userID{id: 7, region: "eu", parent: &userID{id: 1}}
+
+	var out UserIDDto
+	err := Map(&out, from)
+	assert.Nil(t, err)
+	assert.Zero(t, out)
+
+	err = Map(&out, from, WithUnexportedSources(true))
+	assert.Nil(t, err)
+	assert.Equal(t, UserIDDto{Id: 7, Region: "eu"}, out)
+
+	var legacy legacyProduct
+	err = Map(&legacy, struct {
+		id   int
+		name string
+		tags []string
+	}{3, "Hat", []string{"winter"}},
+		WithUnexportedSources(true), WithUnexportedFields(true))
+	assert.Nil(t, err)
+	assert.Equal(t, legacyProduct{id: 3, name: "Hat"}, legacy)
+}
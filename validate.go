@@ -0,0 +1,156 @@
+package dto
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError indicates that a mapped value violates a constraint tag
+type ValidationError struct {
+	Path  string
+	Tag   string
+	Value interface{}
+}
+
+func (ve ValidationError) Error() string {
+	if ve.Tag == "required" {
+		return fmt.Sprintf("Required value at %v is missing", ve.Path)
+	}
+	return fmt.Sprintf("Value %v at %v violates %v", ve.Value, ve.Path, ve.Tag)
+}
+
+// ValidationErrors contains all constraint violations of a Map call
+type ValidationErrors []ValidationError
+
+func (ves ValidationErrors) Error() string {
+	msgs := make([]string, len(ves))
+	for i, ve := range ves {
+		msgs[i] = ve.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ==================================== Constraints ===========================
+
+// Check a mapped destination value against the constraint tags of its field.
+// Violations are collected, so that mapping continues. Nil pointers are not checked,
+// unless the field is required.
+func (m *mapping) validateField(rv reflect.Value, tags fieldTags) error {
+	if tags.required && rv.IsZero() {
+		m.addViolation(rv, "required")
+		return nil
+	}
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if len(tags.oneOf) > 0 {
+		tag := "oneof=" + strings.Join(tags.oneOf, " ")
+		ok, err := valueOneOf(rv, tags.oneOf)
+		if err != nil {
+			return TagError{Tag: tag, Type: rv.Type(), Reason: err.Error()}
+		}
+		if !ok {
+			m.addViolation(rv, tag)
+		}
+	}
+	if tags.min != "" {
+		cmp, err := compareBound(rv, tags.min)
+		if err != nil {
+			return TagError{Tag: "min", Type: rv.Type(), Reason: err.Error()}
+		}
+		if cmp < 0 {
+			m.addViolation(rv, "min="+tags.min)
+		}
+	}
+	if tags.max != "" {
+		cmp, err := compareBound(rv, tags.max)
+		if err != nil {
+			return TagError{Tag: "max", Type: rv.Type(), Reason: err.Error()}
+		}
+		if cmp > 0 {
+			m.addViolation(rv, "max="+tags.max)
+		}
+	}
+	if tags.pattern != "" {
+		if tags.patternRe == nil || rv.Kind() != reflect.String {
+			return TagError{Tag: "pattern", Type: rv.Type(), Reason: "invalid pattern or not a string"}
+		}
+		if !tags.patternRe.MatchString(rv.String()) {
+			m.addViolation(rv, "pattern="+tags.pattern)
+		}
+	}
+	return nil
+}
+
+// Record a required field without a source at the current path
+func (m *mapping) addMissingRequired() {
+	m.violations = append(m.violations, ValidationError{Path: m.pathString(), Tag: "required"})
+}
+
+func (m *mapping) addViolation(rv reflect.Value, tag string) {
+	m.violations = append(m.violations, ValidationError{Path: m.pathString(), Tag: tag, Value: rv.Interface()})
+}
+
+// Check if a value equals one of the tokens, parsed according to its kind
+func valueOneOf(rv reflect.Value, tokens []string) (bool, error) {
+	for _, token := range tokens {
+		equal, err := valueEquals(rv, token)
+		if err != nil {
+			return false, err
+		}
+		if equal {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func valueEquals(rv reflect.Value, token string) (bool, error) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(token, 10, 64)
+		return err == nil && rv.Int() == v, err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := strconv.ParseUint(token, 10, 64)
+		return err == nil && rv.Uint() == v, err
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(token, 64)
+		return err == nil && rv.Float() == v, err
+	case reflect.String:
+		return rv.String() == token, nil
+	case reflect.Bool:
+		v, err := strconv.ParseBool(token)
+		return err == nil && rv.Bool() == v, err
+	}
+	return false, fmt.Errorf("not a basic type")
+}
+
+// Compare a numeric value or the length of a string, slice or map to a bound.
+// Returns -1, 0 or 1.
+func compareBound(rv reflect.Value, bound string) (int, error) {
+	switch rv.Kind() {
+	case reflect.String:
+		v, err := strconv.Atoi(bound)
+		n := utf8.RuneCountInString(rv.String())
+		return compareOrdered(n < v, n > v), err
+	case reflect.Slice, reflect.Map, reflect.Array:
+		v, err := strconv.Atoi(bound)
+		return compareOrdered(rv.Len() < v, rv.Len() > v), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(bound, 10, 64)
+		return compareOrdered(rv.Int() < v, rv.Int() > v), err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := strconv.ParseUint(bound, 10, 64)
+		return compareOrdered(rv.Uint() < v, rv.Uint() > v), err
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(bound, 64)
+		return compareOrdered(rv.Float() < v, rv.Float() > v), err
+	}
+	return 0, fmt.Errorf("not a number or collection")
+}
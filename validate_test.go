@@ -0,0 +1,135 @@
+package dto
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Values outside of oneof sets fail with their path
+func TestOneOf(t *testing.T) {
+	type Status int
+	type OrderDto struct {
+		Status Status `dto:"oneof=1 2 3"`
+		Kind   string `dto:"oneof=retail wholesale"`
+	}
+	type Order struct {
+		Status int
+		Kind   string
+	}
+
+	var out struct{ Orders []OrderDto }
+	err := Map(&out, struct{ Orders []Order }{[]Order{{1, "retail"}, {3, "wholesale"}}})
+	assert.Nil(t, err)
+
+	err = Map(&out, struct{ Orders []Order }{[]Order{{1, "retail"}, {7, "retail"}}})
+	assert.Equal(t, ValidationErrors{{Path: "Orders[1].Status", Tag: "oneof=1 2 3", Value: Status(7)}}, err)
+}
+
+// Numeric values are checked against min and max bounds
+func TestMinMax(t *testing.T) {
+	type ProductDto struct {
+		Stock *uint   `dto:"max=100"`
+		Price float32 `dto:"min=0.5,max=1000"`
+		Name  string
+	}
+	stock := uint(10)
+	from := struct {
+		Stock *uint
+		Price float32
+	}{&stock, 0.5}
+
+	var out ProductDto
+	err := Map(&out, from)
+	assert.Nil(t, err)
+
+	from.Price = 0.1
+	err = Map(&out, from)
+	assert.Equal(t, ValidationErrors{{Path: "Price", Tag: "min=0.5", Value: float32(0.1)}}, err)
+
+	from.Price = 1
+	stock = 101
+	err = Map(&out, from)
+	assert.Equal(t, ValidationErrors{{Path: "Stock", Tag: "max=100", Value: uint(101)}}, err)
+}
+
+// Invalid bounds are reported as tag errors
+func TestInvalidBound(t *testing.T) {
+	var out struct {
+		Count int `dto:"max=many"`
+		Name  string
+	}
+	err := Map(&out, struct{ Count int }{1})
+	assert.ErrorAs(t, err, &TagError{})
+}
+
+// String lengths and patterns are checked, all violations are reported
+func TestStringConstraints(t *testing.T) {
+	type UserDto struct {
+		Name  string   `dto:"min=2,max=5"`
+		Login string   `dto:"pattern=^[a-z]{2,8}$"`
+		Tags  []string `dto:"max=2"`
+		Email string
+	}
+	type User struct {
+		Name  string
+		Login string
+		Tags  []string
+	}
+
+	var out []UserDto
+	err := Map(&out, []User{{"Bob", "bob", nil}, {"Jürgen", "jürgen", []string{"a", "b", "c"}}})
+	assert.IsType(t, ValidationErrors{}, err)
+	assert.ElementsMatch(t, ValidationErrors{
+		{Path: "[1].Name", Tag: "max=5", Value: "Jürgen"},
+		{Path: "[1].Login", Tag: "pattern=^[a-z]{2,8}$", Value: "jürgen"},
+		{Path: "[1].Tags", Tag: "max=2", Value: []string{"a", "b", "c"}},
+	}, err)
+	assert.Equal(t, "Bob", out[0].Name)
+
+	out = nil
+	err = Map(&out, []User{{"Ann", "ann", []string{"a"}}})
+	assert.Nil(t, err)
+}
+
+// Required fields must have a source and a non-zero value
+func TestRequiredTag(t *testing.T) {
+	type User struct {
+		Name  string
+		Email *string
+	}
+	type UserDto struct {
+		Name  string  `dto:"required"`
+		Email *string `dto:"required"`
+		Phone string  `dto:"required"`
+	}
+	email := "bob@mail.com"
+	dto := UserDto{}
+	err := Map(&dto, User{Email: &email})
+	var ves ValidationErrors
+	assert.True(t, errors.As(err, &ves))
+	sort.Slice(ves, func(i, j int) bool { return ves[i].Path < ves[j].Path })
+	assert.Equal(t, ValidationErrors{
+		{Path: "Name", Tag: "required", Value: ""},
+		{Path: "Phone", Tag: "required"},
+	}, ves)
+	assert.Equal(t, "Required value at Name is missing", ves[0].Error())
+
+	err = Map(&dto, struct {
+		User
+		Phone string
+	}{User: User{Name: "Bob"}, Phone: "123"})
+	assert.True(t, errors.As(err, &ves))
+	assert.Len(t, ves, 1)
+	assert.Equal(t, "Email", ves[0].Path)
+
+	// missing sources are found by Validate
+	mapper := Mapper{}
+	mapper.RegisterPair(UserDto{}, User{})
+	var ce ConfigError
+	assert.True(t, errors.As(mapper.Validate(), &ce))
+	assert.Equal(t, FieldNotFoundError{Type: reflect.TypeOf(User{}), Field: "Phone"}, ce[0].Err)
+}